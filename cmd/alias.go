@@ -3,34 +3,104 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
 	"github.com/urfave/cli/v2"
 )
 
 var Alias = &cli.Command{
-	Name:  "alias",
-	Usage: "Manage aliases for JFrog CLI versions",
+	Name:        "alias",
+	Usage:       "Manage aliases for JFrog CLI versions",
+	Description: descriptions.Alias.Format(),
 	Subcommands: []*cli.Command{
 		{
 			Name:      "set",
-			Usage:     "Set an alias (e.g., prod => 2.57.0)",
-			ArgsUsage: "<alias> <version>",
+			Usage:     "Set an alias (e.g., prod => 2.57.0, stable => ^2.74)",
+			ArgsUsage: "<alias> [version]",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Set the alias even if the version is not installed",
+					Value: false,
+				},
+				&cli.StringFlag{
+					Name:  "track",
+					Usage: "Follow a moving target: 'latest' or a version range such as ^2.74",
+				},
+				&cli.DurationFlag{
+					Name:  "refresh-interval",
+					Usage: "Re-resolve a tracking alias when it is used and older than this (e.g. 24h)",
+				},
+			},
 			Action: func(c *cli.Context) error {
-				if c.Args().Len() != 2 {
-					return cli.Exit("Usage: jfvm alias set <alias> <version>", 1)
+				if err := misplacedFlagError(c); err != nil {
+					return err
+				}
+				track := c.String("track")
+				if (track == "" && c.Args().Len() != 2) || (track != "" && c.Args().Len() != 1) {
+					return cli.Exit("Usage: jfvm alias set <alias> <version> or jfvm alias set --track <latest|range> [--refresh-interval <duration>] <alias>", 1)
 				}
 				alias, version := c.Args().Get(0), c.Args().Get(1)
 
-				// Prevent using "latest" as an alias since it's a reserved keyword
-				if strings.ToLower(alias) == "latest" {
-					return cli.Exit("'latest' is a reserved keyword and cannot be used as an alias", 1)
+				if err := utils.ValidateAliasName(alias); err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+
+				meta, err := utils.LoadAliasMeta(alias)
+				if err != nil {
+					return fmt.Errorf("failed to read alias metadata: %w", err)
+				}
+
+				if track != "" {
+					if !strings.EqualFold(track, "latest") {
+						if _, err := utils.ParseVersionRange(track); err != nil {
+							return cli.Exit(err.Error(), 1)
+						}
+					}
+					meta.Track = track
+					meta.RefreshInterval = ""
+					if interval := c.Duration("refresh-interval"); interval > 0 {
+						meta.RefreshInterval = interval.String()
+					}
+					if err := utils.SaveAliasMeta(alias, meta); err != nil {
+						return err
+					}
+					return refreshTrackedAlias(alias)
+				}
+
+				if c.IsSet("refresh-interval") {
+					return cli.Exit("--refresh-interval only applies together with --track", 1)
+				}
+
+				// Pointing an alias at another alias stores the version it currently resolves to
+				if resolved, err := utils.ResolveAlias(version); err == nil {
+					fmt.Printf("'%s' is an alias for %s, pointing '%s' at %s\n", version, resolved, alias, resolved)
+					version = resolved
 				}
 
-				os.MkdirAll(utils.JfvmAliases, 0755)
-				return os.WriteFile(filepath.Join(utils.JfvmAliases, alias), []byte(version), 0644)
+				if err := utils.CheckVersionExists(version); err != nil && !c.Bool("force") {
+					return cli.Exit(fmt.Sprintf("version %s is not installed (%v). Install it first or use --force", version, err), 1)
+				}
+
+				if err := utils.WriteAlias(alias, version); err != nil {
+					return err
+				}
+
+				// A plain set turns a tracking alias back into a static one
+				if meta.Track != "" {
+					meta, err = utils.LoadAliasMeta(alias)
+					if err != nil {
+						return err
+					}
+					meta.Track, meta.RefreshInterval, meta.Held = "", "", false
+					return utils.SaveAliasMeta(alias, meta)
+				}
+				return nil
 			},
 		},
 		{
@@ -49,6 +119,114 @@ var Alias = &cli.Command{
 				return nil
 			},
 		},
+		{
+			Name:  "list",
+			Usage: "List all aliases with their target versions",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "no-color",
+					Usage: "Disable colored output",
+					Value: false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.Bool("no-color") {
+					color.NoColor = true
+				}
+
+				names, targets, err := utils.ListAliases()
+				if err != nil {
+					return err
+				}
+				manifest, err := utils.FindProjectManifest()
+				if err != nil {
+					return err
+				}
+				if len(names) == 0 && (manifest == nil || len(manifest.Aliases) == 0) {
+					fmt.Println("📭 No aliases defined.")
+					return nil
+				}
+
+				var (
+					greenColor = color.New(color.FgGreen)
+					redColor   = color.New(color.FgRed)
+				)
+
+				fmt.Printf("%-20s %-20s %-18s %s\n", "ALIAS", "VERSION", "STATUS", "TRACKS")
+				fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+				for _, name := range names {
+					status := greenColor.Sprintf("%-18s", "✓ installed")
+					if utils.CheckVersionExists(targets[name]) != nil {
+						status = redColor.Sprintf("%-18s", "✗ not installed")
+					}
+					tracks := ""
+					if manifest != nil {
+						if _, ok := manifest.Aliases[name]; ok {
+							tracks = "shadowed by project alias"
+						}
+					}
+					if meta, err := utils.LoadAliasMeta(name); err == nil && meta.Track != "" {
+						tracks = strings.TrimSpace(meta.Track + " " + tracks)
+						if meta.RefreshInterval != "" {
+							tracks += fmt.Sprintf(" (every %s)", meta.RefreshInterval)
+						}
+						if meta.Held {
+							tracks += " (held after rollback)"
+						}
+					}
+					fmt.Printf("%-20s %-20s %s %s\n", name, targets[name], status, tracks)
+				}
+				if manifest != nil {
+					for _, name := range manifest.AliasNames() {
+						version := manifest.Aliases[name]
+						status := greenColor.Sprintf("%-18s", "✓ installed")
+						if utils.CheckVersionExists(version) != nil {
+							status = redColor.Sprintf("%-18s", "✗ not installed")
+						}
+						fmt.Printf("%-20s %-20s %s %s\n", name, version, status, "project ("+manifest.Path+")")
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "prune",
+			Usage: "Remove aliases that point at versions which are not installed",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only show which aliases would be removed",
+					Value: false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				names, targets, err := utils.ListAliases()
+				if err != nil {
+					return err
+				}
+
+				pruned := 0
+				for _, name := range names {
+					if utils.CheckVersionExists(targets[name]) == nil {
+						continue
+					}
+					if c.Bool("dry-run") {
+						fmt.Printf("Would remove alias '%s' (%s is not installed)\n", name, targets[name])
+					} else {
+						if err := utils.RemoveAlias(name); err != nil {
+							return fmt.Errorf("failed to remove alias '%s': %w", name, err)
+						}
+						fmt.Printf("🗑️  Removed alias '%s' (%s is not installed)\n", name, targets[name])
+					}
+					pruned++
+				}
+
+				if pruned == 0 {
+					fmt.Println("✅ No dangling aliases found.")
+				}
+				return nil
+			},
+		},
 		{
 			Name:      "remove",
 			Usage:     "Remove an alias",
@@ -57,8 +235,167 @@ var Alias = &cli.Command{
 				if c.Args().Len() != 1 {
 					return cli.Exit("Usage: jfvm alias remove <alias>", 1)
 				}
-				return os.Remove(filepath.Join(utils.JfvmAliases, c.Args().Get(0)))
+				alias := c.Args().Get(0)
+				if _, err := utils.ResolvePersonalAlias(alias); err != nil {
+					return fmt.Errorf("alias '%s' does not exist", alias)
+				}
+				return utils.RemoveAlias(alias)
+			},
+		},
+		{
+			Name:      "refresh",
+			Usage:     "Re-resolve tracking aliases (all of them if none is given)",
+			ArgsUsage: "[alias...]",
+			Action: func(c *cli.Context) error {
+				names := c.Args().Slice()
+				if len(names) == 0 {
+					all, _, err := utils.ListAliases()
+					if err != nil {
+						return err
+					}
+					for _, name := range all {
+						if meta, err := utils.LoadAliasMeta(name); err == nil && meta.Track != "" {
+							names = append(names, name)
+						}
+					}
+					if len(names) == 0 {
+						fmt.Println("📭 No tracking aliases defined.")
+						return nil
+					}
+				}
+
+				for _, name := range names {
+					if err := refreshTrackedAlias(name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "rollback",
+			Usage:     "Point an alias back at its previous target",
+			ArgsUsage: "<alias>",
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					return cli.Exit("Usage: jfvm alias rollback <alias>", 1)
+				}
+				alias := c.Args().Get(0)
+				if _, err := utils.ResolvePersonalAlias(alias); err != nil {
+					return fmt.Errorf("alias '%s' does not exist", alias)
+				}
+
+				version, err := utils.RollbackAlias(alias)
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				fmt.Printf("⏪ Alias '%s' now points at %s\n", alias, version)
+				if err := utils.CheckVersionExists(version); err != nil {
+					fmt.Printf("⚠️  %s is not installed; run 'jfvm install %s' before using the alias\n", version, version)
+				}
+
+				// Keep a tracking alias where it was rolled back to until it is refreshed explicitly
+				meta, err := utils.LoadAliasMeta(alias)
+				if err != nil {
+					return err
+				}
+				if meta.Track != "" {
+					meta.Held = true
+					if err := utils.SaveAliasMeta(alias, meta); err != nil {
+						return err
+					}
+					fmt.Printf("⏸️  Tracking of %s is held; run 'jfvm alias refresh %s' to resume\n", meta.Track, alias)
+				}
+				return nil
 			},
 		},
 	},
 }
+
+// resolveTrackTarget finds the version a tracking spec currently selects. Ranges are matched
+// against the published releases, or against the installed versions when offline.
+func resolveTrackTarget(track string) (string, error) {
+	if strings.EqualFold(track, "latest") {
+		return utils.GetLatestVersion()
+	}
+
+	versionRange, err := utils.ParseVersionRange(track)
+	if err != nil {
+		return "", err
+	}
+
+	available, err := utils.GetAvailableVersions()
+	if err != nil {
+		fmt.Printf("⚠️  %v, falling back to installed versions\n", err)
+		available, err = utils.ListInstalledVersions()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	version, ok := versionRange.HighestMatching(available)
+	if !ok {
+		return "", fmt.Errorf("no version matches %s", track)
+	}
+	return version, nil
+}
+
+// refreshTrackedAlias re-resolves a tracking alias, installing the new target when needed.
+func refreshTrackedAlias(alias string) error {
+	meta, err := utils.LoadAliasMeta(alias)
+	if err != nil {
+		return fmt.Errorf("failed to read alias metadata: %w", err)
+	}
+	if meta.Track == "" {
+		return cli.Exit(fmt.Sprintf("alias '%s' does not track a version; set it with --track", alias), 1)
+	}
+
+	version, err := resolveTrackTarget(meta.Track)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s for alias '%s': %w", meta.Track, alias, err)
+	}
+
+	if err := utils.CheckVersionExists(version); err != nil {
+		fmt.Printf("Version %s not found locally. Installing...\n", version)
+		if err := internal.DownloadAndInstall(version); err != nil {
+			return fmt.Errorf("failed to install %s: %w", version, err)
+		}
+	}
+
+	previous, _ := utils.ResolvePersonalAlias(alias)
+	if err := utils.WriteAlias(alias, version); err != nil {
+		return err
+	}
+
+	// WriteAlias recorded the previous target, so reload before updating the refresh state
+	meta, err = utils.LoadAliasMeta(alias)
+	if err != nil {
+		return err
+	}
+	meta.LastRefresh = time.Now()
+	meta.Held = false
+	if err := utils.SaveAliasMeta(alias, meta); err != nil {
+		return err
+	}
+
+	if previous == version {
+		fmt.Printf("✅ Alias '%s' (%s) is up to date at %s\n", alias, meta.Track, version)
+	} else if previous == "" {
+		fmt.Printf("✅ Alias '%s' (%s) points at %s\n", alias, meta.Track, version)
+	} else {
+		fmt.Printf("⏩ Alias '%s' (%s) moved from %s to %s\n", alias, meta.Track, previous, version)
+	}
+	return nil
+}
+
+// refreshAliasIfDue re-resolves a tracking alias whose refresh interval has elapsed.
+// Failures are reported but don't prevent the alias from being used at its current target.
+func refreshAliasIfDue(alias string) {
+	meta, err := utils.LoadAliasMeta(alias)
+	if err != nil || !meta.RefreshDue() {
+		return
+	}
+	if err := refreshTrackedAlias(alias); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not refresh alias '%s': %v\n", alias, err)
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestAliasSetTrackExample(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.73.9", "2.74.0", "2.74.3"} {
+		installTestVersion(t, version)
+	}
+	// GitHub is out of reach, so the range resolves against the installed versions
+	t.Setenv("HTTPS_PROXY", "http://127.0.0.1:1")
+
+	example := documentedExample(t, descriptions.Alias, "--track")
+	if err := runCommandLine(t, example); err != nil {
+		t.Fatalf("%s: %v", example, err)
+	}
+
+	version, err := utils.ResolvePersonalAlias("stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "2.74.3" {
+		t.Errorf("stable points at %s, want 2.74.3", version)
+	}
+	meta, err := utils.LoadAliasMeta("stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Track != "^2.74" || meta.RefreshInterval != "24h0m0s" {
+		t.Errorf("stable tracks %q every %q, want ^2.74 every 24h0m0s", meta.Track, meta.RefreshInterval)
+	}
+}
+
+func TestAliasSetRejectsFlagsAfterAlias(t *testing.T) {
+	useTestRoot(t)
+	err := runCommandLine(t, "jfvm alias set stable --track ^2.74")
+	if err == nil {
+		t.Fatal("expected an error for --track after the alias name")
+	}
+	if _, err := utils.ResolvePersonalAlias("stable"); err == nil {
+		t.Error("alias was created although the command failed")
+	}
+}
+
+func TestAliasSetNeedsInstalledVersion(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+
+	if err := runCommandLine(t, "jfvm alias set next 2.99.0"); err == nil {
+		t.Error("an alias for a version that is not installed was accepted without --force")
+	}
+	if err := runCommandLine(t, "jfvm alias set prod 2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+	// An alias of an alias stores the version, so it survives the other alias moving
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm alias set ci prod"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if version, _ := utils.ResolvePersonalAlias("ci"); version != "2.70.0" {
+		t.Errorf("ci points at %q, want 2.70.0", version)
+	}
+	if err := runCommandLine(t, "jfvm alias set --force next 2.99.0"); err != nil {
+		t.Errorf("--force: %v", err)
+	}
+}
+
+func TestAliasPruneRemovesDanglingAliases(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+	for name, version := range map[string]string{"prod": "2.70.0", "gone": "2.69.0"} {
+		if err := utils.WriteAlias(name, version); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm alias prune --dry-run"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "Would remove alias 'gone'") || strings.Contains(stdout, "'prod'") {
+		t.Errorf("unexpected dry run:\n%s", stdout)
+	}
+	if _, err := utils.ResolvePersonalAlias("gone"); err != nil {
+		t.Error("--dry-run removed the alias")
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm alias prune"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	names, _, err := utils.ListAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"prod"}) {
+		t.Errorf("aliases after prune: %v, want [prod]", names)
+	}
+}
+
+func TestRemoveKeepsAliasedVersions(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+	if err := utils.WriteAlias("prod", "2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	captureStdout(t, func() { err = runCommandLine(t, "jfvm remove --yes 2.70.0") })
+	if err == nil || !strings.Contains(err.Error(), "all matching versions are protected") {
+		t.Errorf("got %v, want the aliased version protected", err)
+	}
+	if err := utils.CheckVersionExists("2.70.0"); err != nil {
+		t.Error("the aliased version was removed")
+	}
+}
+
+func TestAliasRollbackHoldsTrackingAlias(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.74.0")
+	installTestVersion(t, "2.74.3")
+	t.Setenv("HTTPS_PROXY", "http://127.0.0.1:1")
+	if err := utils.WriteAlias("stable", "2.74.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm alias set --track ^2.74 stable"); err != nil {
+			t.Fatal(err)
+		}
+		if err := runCommandLine(t, "jfvm alias rollback stable"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	meta, err := utils.LoadAliasMeta("stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := utils.ResolvePersonalAlias("stable"); version != "2.74.0" || !meta.Held {
+		t.Errorf("stable points at %s (held %v), want 2.74.0 held", version, meta.Held)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm alias refresh stable"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	meta, _ = utils.LoadAliasMeta("stable")
+	if version, _ := utils.ResolvePersonalAlias("stable"); version != "2.74.3" || meta.Held {
+		t.Errorf("after refresh stable points at %s (held %v), want 2.74.3", version, meta.Held)
+	}
+}
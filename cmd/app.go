@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// NewApp returns the jfvm command line application.
+func NewApp() *cli.App {
+	return &cli.App{
+		Name:                 "jfvm",
+		Usage:                "Manage multiple versions of JFrog CLI",
+		EnableBashCompletion: true,
+		// Regex flags like --replace 'x{1,3}=>X' must reach the command intact; flags that
+		// take lists split their values with commaSeparated instead
+		DisableSliceFlagSeparator: true,
+		Commands: []*cli.Command{
+			Install,
+			Use,
+			List,
+			Remove,
+			Clear,
+			Alias,
+			Link,
+			Compare,
+			Benchmark,
+			History,
+			Doctor,
+			Setup,
+			Sync,
+			Rollback,
+			Prune,
+			Du,
+			Trash,
+			Restore,
+			Snapshot,
+		},
+	}
+}
+
+// commaSeparated splits the values of a repeatable flag that also accepts comma-separated lists.
+func commaSeparated(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
+
+// misplacedFlag returns the first argument before any "--" that looks like a flag. urfave/cli
+// stops parsing flags at the first positional argument, so later flags end up as arguments.
+func misplacedFlag(c *cli.Context) string {
+	for _, arg := range c.Args().Slice() {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			return arg
+		}
+	}
+	return ""
+}
+
+// misplacedFlagError explains a flag given after the arguments, or returns nil.
+func misplacedFlagError(c *cli.Context) error {
+	if flag := misplacedFlag(c); flag != "" {
+		return cli.Exit(fmt.Sprintf("%s must come before the arguments: flags after the first argument are not parsed", flag), 1)
+	}
+	return nil
+}
@@ -57,6 +57,20 @@ var Benchmark = &cli.Command{
 			Usage: "Output format: table, json, csv",
 			Value: "table",
 		},
+		&cli.BoolFlag{
+			Name:  "isolate-home",
+			Usage: "Run each version with its own temporary JFROG_CLI_HOME_DIR, seeded from a copy of the current one",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "replay",
+			Usage: "Serve HTTP(S) traffic from the named recording, recording it in an unmeasured run of the first version if it doesn't exist yet (implies --isolate-home)",
+		},
+		&cli.BoolFlag{
+			Name:  "rerecord",
+			Usage: "Record the --replay recording again, replacing the existing one",
+			Value: false,
+		},
 	},
 	Action: func(c *cli.Context) error {
 		// Parse and validate arguments
@@ -73,6 +87,16 @@ var Benchmark = &cli.Command{
 
 		// Extract configuration
 		config := extractBenchmarkConfig(c)
+		replay, ca, err := proxyFlags(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if ca != nil {
+			config.ProxyCA, config.IsolateHome = ca, true
+			if config.Replay, err = utils.NewHTTPReplay(replay, ca, c.Bool("rerecord")); err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+		}
 
 		// Run benchmarks
 		results, err := runBenchmarks(resolvedVersions, jfCommand, config)
@@ -88,11 +112,14 @@ var Benchmark = &cli.Command{
 }
 
 type BenchmarkConfig struct {
-	Iterations int
-	Timeout    time.Duration
-	Format     string
-	NoColor    bool
-	Detailed   bool
+	Iterations  int
+	Timeout     time.Duration
+	Format      string
+	NoColor     bool
+	Detailed    bool
+	IsolateHome bool
+	Replay      *utils.HTTPReplay
+	ProxyCA     *utils.ProxyCA
 }
 
 func parseArguments(args []string) (versions []string, jfCommand []string, err error) {
@@ -146,11 +173,12 @@ func validateVersions(versions []string) ([]string, error) {
 
 func extractBenchmarkConfig(c *cli.Context) BenchmarkConfig {
 	return BenchmarkConfig{
-		Iterations: c.Int("iterations"),
-		Timeout:    time.Duration(c.Int("timeout")) * time.Second,
-		Format:     c.String("format"),
-		NoColor:    c.Bool("no-color"),
-		Detailed:   c.Bool("detailed"),
+		Iterations:  c.Int("iterations"),
+		Timeout:     time.Duration(c.Int("timeout")) * time.Second,
+		Format:      c.String("format"),
+		NoColor:     c.Bool("no-color"),
+		Detailed:    c.Bool("detailed"),
+		IsolateHome: c.Bool("isolate-home"),
 	}
 }
 
@@ -159,7 +187,32 @@ func runBenchmarks(versions []string, jfCommand []string, config BenchmarkConfig
 	if config.Format == "table" {
 		fmt.Printf("🏁 Benchmarking JFrog CLI versions: %s\n", strings.Join(versions, ", "))
 		fmt.Printf("📝 Command: jf %s\n", strings.Join(jfCommand, " "))
-		fmt.Printf("🔄 Iterations: %d per version\n\n", config.Iterations)
+		fmt.Printf("🔄 Iterations: %d per version\n", config.Iterations)
+		if config.IsolateHome {
+			fmt.Printf("🏠 Each version runs with its own copy of %s\n", utils.JfrogCliHome())
+		}
+		if config.Replay != nil {
+			fmt.Printf("📼 Replaying HTTP traffic from recording %s\n", config.Replay.Name())
+		}
+		fmt.Println()
+	}
+
+	// An unmeasured first run records the traffic all measured runs replay
+	if config.Replay != nil && config.Replay.Recording() {
+		run, cleanup, err := isolateRun(versions[0], RunOptions{IsolateHome: config.IsolateHome, Replay: config.Replay, ProxyCA: config.ProxyCA})
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		_, err = executeJFCommand(ctx, versions[0], jfCommand, run)
+		cancel()
+		cleanup()
+		if err != nil {
+			return nil, err
+		}
+		if err := recordReplay(config.Replay, versions[0]); err != nil {
+			return nil, err
+		}
 	}
 
 	// Run benchmarks
@@ -169,7 +222,12 @@ func runBenchmarks(versions []string, jfCommand []string, config BenchmarkConfig
 	for i, version := range versions {
 		i, version := i, version
 		g.Go(func() error {
-			result, err := runBenchmark(ctx, version, jfCommand, config.Iterations, config.Timeout)
+			run, cleanup, err := isolateRun(version, RunOptions{IsolateHome: config.IsolateHome, Replay: config.Replay, ProxyCA: config.ProxyCA})
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			result, err := runBenchmark(ctx, version, jfCommand, config.Iterations, config.Timeout, run)
 			results[i] = result
 			return err
 		})
@@ -178,7 +236,7 @@ func runBenchmarks(versions []string, jfCommand []string, config BenchmarkConfig
 	return results, g.Wait()
 }
 
-func runBenchmark(ctx context.Context, version string, jfCommand []string, iterations int, timeout time.Duration) (BenchmarkResult, error) {
+func runBenchmark(ctx context.Context, version string, jfCommand []string, iterations int, timeout time.Duration, run RunOptions) (BenchmarkResult, error) {
 	result := BenchmarkResult{
 		Version:    version,
 		Iterations: iterations,
@@ -190,7 +248,7 @@ func runBenchmark(ctx context.Context, version string, jfCommand []string, itera
 
 	for i := 0; i < iterations; i++ {
 		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
-		exec, err := executeJFCommand(timeoutCtx, version, jfCommand)
+		exec, err := executeJFCommand(timeoutCtx, version, jfCommand, run)
 		cancel()
 
 		result.Executions[i] = exec
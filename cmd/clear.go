@@ -2,21 +2,70 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"slices"
 
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
 	"github.com/urfave/cli/v2"
 )
 
 var Clear = &cli.Command{
-	Name:  "clear",
-	Usage: "Remove all installed JFrog CLI versions",
+	Name:        "clear",
+	Usage:       "Remove all installed JFrog CLI versions",
+	Description: descriptions.Clear.Format(),
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "keep",
+			Usage: "Versions, globs or ranges to keep (can be repeated or comma-separated)",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Don't ask for confirmation",
+			Value:   false,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only show which versions would be removed",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Also remove the active version and versions aliases point at",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "purge",
+			Usage: "Delete release versions permanently instead of moving them to the trash",
+			Value: false,
+		},
+	},
 	Action: func(c *cli.Context) error {
-		err := os.RemoveAll(utils.JfvmVersions)
+		installed, err := utils.ListInstalledVersions()
 		if err != nil {
-			return fmt.Errorf("failed to clear versions: %w", err)
+			return fmt.Errorf("failed to list versions: %w", err)
+		}
+
+		kept, err := matchVersions(commaSeparated(c.StringSlice("keep")), installed)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		var candidates []string
+		for _, version := range installed {
+			if !slices.Contains(kept, version) {
+				candidates = append(candidates, version)
+			}
 		}
-		fmt.Println("All versions removed.")
-		return nil
+		for _, version := range kept {
+			fmt.Printf("📌 Keeping %s\n", version)
+		}
+
+		targets := excludeProtected(candidates, c.Bool("force"))
+		if len(targets) == 0 {
+			fmt.Println("✅ Nothing to remove.")
+			return nil
+		}
+
+		return removeVersions(targets, c.Bool("yes"), c.Bool("dry-run"), c.Bool("purge"))
 	},
 }
@@ -3,35 +3,175 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
-	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
 )
 
+// Channels of a result that compare can diff.
+const (
+	ChannelStdout  = "stdout"
+	ChannelStderr  = "stderr"
+	ChannelExit    = "exit"
+	ChannelFiles   = "files"
+	ChannelNetwork = "network"
+)
+
+var allChannels = []string{ChannelStdout, ChannelStderr, ChannelExit, ChannelFiles, ChannelNetwork}
+
+// defaultChannels are compared unless --channels says otherwise; files are added with
+// --fs-diff and network with --net-diff.
+var defaultChannels = []string{ChannelStdout, ChannelStderr, ChannelExit}
+
+// ExecutionResult is one run of a command. Output is stdout; ErrorMsg is only set when the
+// command could not be run or timed out.
 type ExecutionResult struct {
-	Version   string
-	Command   string
-	Output    string
-	ErrorMsg  string
-	ExitCode  int
-	Duration  time.Duration
-	StartTime time.Time
+	Version   string        `json:"version"`
+	Command   string        `json:"command"`
+	Output    string        `json:"output"`
+	Stderr    string        `json:"stderr"`
+	ErrorMsg  string        `json:"error,omitempty"`
+	ExitCode  int           `json:"exit_code"`
+	Duration  time.Duration `json:"duration_ns"`
+	StartTime time.Time     `json:"start_time"`
+	// Files is the working directory the command left behind, when it ran in a scratch directory
+	Files []utils.FileEntry `json:"files,omitempty"`
+	// Calls are the HTTP requests the command sent, in order, when they were traced
+	Calls []utils.HTTPCall `json:"calls,omitempty"`
+}
+
+// RunOptions are how a command is run beyond its arguments.
+type RunOptions struct {
+	// Env is added to the environment of the command
+	Env   []string
+	Stdin string
+	// IsolateHome gives each version its own copy of the JFrog CLI home
+	IsolateHome bool
+	// Scratch runs each version in its own empty working directory, whose files are diffed
+	Scratch bool
+	// Seed is a directory copied into each scratch directory
+	Seed string
+	// Replay routes HTTP(S) traffic through a proxy that records it or replays a recording
+	Replay *utils.HTTPReplay
+	// NetDiff routes HTTP(S) traffic through a proxy that logs the calls of each version
+	NetDiff bool
+	// ProxyCA is the certificate authority of the proxy, set with Replay or NetDiff
+	ProxyCA *utils.ProxyCA
+	// Slots bounds the jf processes running at once across all runs that share it; nil
+	// runs every version at the same time
+	Slots chan struct{}
+
+	// cliHome is the isolated JFrog CLI home of this run
+	cliHome string
+	// workdir is the scratch directory of this run
+	workdir string
+}
+
+// isolateRun gives version its own JFrog CLI home and scratch directory when run asks for
+// them. The caller must call cleanup once the version is done.
+func isolateRun(version string, run RunOptions) (RunOptions, func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for _, f := range cleanups {
+			f()
+		}
+	}
+
+	if run.IsolateHome {
+		dir, removeHome, err := utils.NewIsolatedCliHome(version)
+		if err != nil {
+			return run, nil, err
+		}
+		cleanups = append(cleanups, removeHome)
+		run.Env = append(slices.Clone(run.Env), "JFROG_CLI_HOME_DIR="+dir)
+		run.cliHome = dir
+		if run.ProxyCA != nil {
+			if err := run.ProxyCA.InstallIn(dir); err != nil {
+				cleanup()
+				return run, nil, fmt.Errorf("failed to trust the recording proxy: %w", err)
+			}
+		}
+	}
+
+	if run.Scratch {
+		dir, err := os.MkdirTemp("", "jfvm-work-"+version+"-")
+		if err != nil {
+			cleanup()
+			return run, nil, fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+		cleanups = append(cleanups, func() { _ = os.RemoveAll(dir) })
+		if run.Seed != "" {
+			if err := utils.CopyTree(run.Seed, dir, nil); err != nil {
+				cleanup()
+				return run, nil, fmt.Errorf("failed to seed scratch directory from %s: %w", run.Seed, err)
+			}
+		}
+		run.workdir = dir
+	}
+	return run, cleanup, nil
+}
+
+// OutputGroup is a set of versions whose results are identical.
+type OutputGroup struct {
+	Label    string
+	Versions []string
+	Result   ExecutionResult
+}
+
+// CompareOptions controls how results are compared and displayed.
+type CompareOptions struct {
+	Unified bool
+	NoColor bool
+	Timing  bool
+	// Context is the number of unchanged lines shown around changes; negative shows all
+	Context int
+	// Width is the side-by-side diff width in columns; 0 uses the terminal width
+	Width int
+	// Channels selects which of stdout, stderr and the exit code count as differences; empty means all
+	Channels []string
+	JSON     JSONDiffOptions
+}
+
+// channels returns the selected channels.
+func (o CompareOptions) channels() []string {
+	if len(o.Channels) == 0 {
+		return defaultChannels
+	}
+	return o.Channels
+}
+
+// compares reports whether differences in the channel count.
+func (o CompareOptions) compares(channel string) bool {
+	return len(o.Channels) == 0 || slices.Contains(o.Channels, channel)
+}
+
+// Comparison holds the results of one command across versions, grouped by identical output.
+// The group containing the baseline version is always first.
+type Comparison struct {
+	Command  string
+	Baseline string
+	Results  []ExecutionResult
+	Groups   []OutputGroup
 }
 
 var Compare = &cli.Command{
 	Name:        "compare",
 	Usage:       descriptions.Compare.Usage,
-	ArgsUsage:   "<version1> <version2> -- <jf-command> [args...]",
+	ArgsUsage:   "<version1> <version2> [version or range...] -- <jf-command> [args...]",
 	Description: descriptions.Compare.Format(),
 	Flags: []cli.Flag{
 		&cli.BoolFlag{
@@ -44,97 +184,636 @@ var Compare = &cli.Command{
 			Usage: "Disable colored output",
 			Value: false,
 		},
+		&cli.IntFlag{
+			Name:  "context",
+			Usage: "Unchanged lines to show around each change; -1 shows the full output",
+			Value: 3,
+		},
+		&cli.IntFlag{
+			Name:  "width",
+			Usage: "Side-by-side diff width in columns (default: terminal width or $COLUMNS)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "channels",
+			Usage: "Result channels that count as differences: stdout, stderr, exit, files (with --fs-diff), network (with --net-diff)",
+			Value: cli.NewStringSlice(defaultChannels...),
+		},
 		&cli.IntFlag{
 			Name:  "timeout",
 			Usage: "Command timeout in seconds",
 			Value: 30,
 		},
+		&cli.BoolFlag{
+			Name:  "isolate-home",
+			Usage: "Run each version with its own temporary JFROG_CLI_HOME_DIR, seeded from a copy of the current one",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "fs-diff",
+			Usage: "Run each version in its own scratch working directory and diff the files it leaves behind",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "seed",
+			Usage: "Directory copied into each scratch working directory (implies --fs-diff)",
+		},
+		&cli.BoolFlag{
+			Name:  "net-diff",
+			Usage: "Route each version through a local proxy and diff the HTTP calls it makes (implies --isolate-home)",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "replay",
+			Usage: "Serve HTTP(S) traffic from the named recording, recording it with the first version if it doesn't exist yet (implies --isolate-home)",
+		},
+		&cli.BoolFlag{
+			Name:  "rerecord",
+			Usage: "Record the --replay recording again, replacing the existing one",
+			Value: false,
+		},
 		&cli.BoolFlag{
 			Name:  "timing",
 			Usage: "Show execution timing information",
 			Value: true,
 		},
+		&cli.StringFlag{
+			Name:  "baseline",
+			Usage: "Version to diff the other outputs against (default: the first version)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "normalize",
+			Usage: "Built-in normalizers to apply before diffing: timestamps, uuids, ids, paths, durations or all",
+		},
+		&cli.StringSliceFlag{
+			Name:  "replace",
+			Usage: "Regex replace rule applied before diffing, as <regex>=><replacement> (can be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "ignore-lines",
+			Usage: "Drop output lines matching this regex before diffing (can be repeated)",
+		},
+		&cli.StringFlag{
+			Name:  "profile",
+			Usage: "Apply a saved normalization profile",
+		},
+		&cli.StringFlag{
+			Name:  "save-profile",
+			Usage: "Save the given --normalize, --replace and --ignore-lines rules as a named profile",
+		},
+		&cli.BoolFlag{
+			Name:  "no-json",
+			Usage: "Diff JSON outputs as text instead of structurally",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "ignore-array-order",
+			Usage: "Treat JSON arrays as unordered when diffing",
+			Value: false,
+		},
+		&cli.StringSliceFlag{
+			Name:  "ignore-path",
+			Usage: "JSON path to leave out of the diff, e.g. $.meta.took or $.files[*].created (can be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:  "fail-on-diff",
+			Usage: "Exit with code 1 when any version's result differs from the baseline",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Report format: text, json, junit, markdown",
+			Value: FormatText,
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "Write the --format report to this file and show the text report on the terminal",
+		},
+		&cli.StringFlag{
+			Name:  "suite",
+			Usage: "Run the cases of a YAML suite file instead of a single command",
+		},
+		&cli.IntFlag{
+			Name:  "parallel",
+			Usage: "jf processes a suite runs at the same time, across all cases and versions (default: the suite's parallel setting, or 4)",
+		},
+		&cli.StringFlag{
+			Name:  "annotations",
+			Usage: "Emit CI annotations for differences: github or gitlab (default: github on GitHub Actions, gitlab with --codequality)",
+		},
+		&cli.StringFlag{
+			Name:  "codequality",
+			Usage: "Write differences to this file as a GitLab code quality report, e.g. gl-code-quality-report.json",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		args := c.Args().Slice()
-		if len(args) < 3 {
-			return cli.Exit("Usage: jfvm compare <version1> <version2> -- <jf-command> [args...]", 1)
-		}
+		usage := "Usage: jfvm compare <version1> <version2> [version or range...] -- <jf-command> [args...]"
 
-		// Find the separator "--"
-		separatorIndex := -1
-		for i, arg := range args {
-			if arg == "--" {
-				separatorIndex = i
-				break
+		rules, err := compareNormalizationRules(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		normalizer, err := rules.Compile()
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if name := c.String("save-profile"); name != "" {
+			if err := utils.SaveCompareProfile(name, rules); err != nil {
+				return fmt.Errorf("failed to save profile: %w", err)
+			}
+			fmt.Printf("💾 Saved normalization profile '%s' (%s)\n", name, rules)
+			if len(args) == 0 {
+				return nil
 			}
 		}
 
-		if separatorIndex == -1 || separatorIndex != 2 {
-			return cli.Exit("Missing '--' separator. Usage: jfvm compare <version1> <version2> -- <jf-command> [args...]", 1)
+		if path := c.String("suite"); path != "" {
+			return runCompareSuite(c, path, args, rules)
 		}
 
-		version1 := args[0]
-		version2 := args[1]
-		jfCommand := args[3:]
+		// Find the separator "--"
+		separatorIndex := slices.Index(args, "--")
+		if separatorIndex == -1 {
+			return cli.Exit("Missing '--' separator. "+usage, 1)
+		}
+		if separatorIndex == 0 {
+			return cli.Exit(usage, 1)
+		}
 
+		jfCommand := args[separatorIndex+1:]
 		if len(jfCommand) == 0 {
 			return cli.Exit("No JFrog CLI command specified after '--'", 1)
 		}
 
-		// Resolve aliases if needed
-		resolved1, err := utils.ResolveVersionOrAlias(version1)
+		versions, err := resolveCompareVersions(args[:separatorIndex])
 		if err != nil {
-			resolved1 = version1
+			return cli.Exit(err.Error(), 1)
+		}
+		if len(versions) < 2 {
+			return cli.Exit("Need at least two versions to compare. "+usage, 1)
 		}
-		resolved2, err := utils.ResolveVersionOrAlias(version2)
+
+		channels, err := parseChannels(c.StringSlice("channels"))
 		if err != nil {
-			resolved2 = version2
+			return cli.Exit(err.Error(), 1)
 		}
 
-		// Check if versions exist
-		if err := utils.CheckVersionExists(resolved1); err != nil {
-			return fmt.Errorf("version %s (%s) not found: %w", version1, resolved1, err)
+		format, showText, err := compareFormat(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
 		}
-		if err := utils.CheckVersionExists(resolved2); err != nil {
-			return fmt.Errorf("version %s (%s) not found: %w", version2, resolved2, err)
+		style, err := compareAnnotationStyle(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		baseline, err := compareBaseline(c, versions)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		run, err := compareRunOptions(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if channels, err = optionalChannels(channels, c.IsSet("channels"), run); err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		replay, ca, err := proxyFlags(c)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if ca != nil {
+			run.ProxyCA, run.IsolateHome = ca, true
+		}
+		if replay != "" {
+			if run.Replay, err = utils.NewHTTPReplay(replay, ca, c.Bool("rerecord")); err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
 		}
 
-		fmt.Printf("🔄 Comparing JFrog CLI versions: %s vs %s\n", version1, version2)
-		fmt.Printf("📝 Command: jf %s\n\n", strings.Join(jfCommand, " "))
-
-		// Execute commands in parallel
-		results := make([]ExecutionResult, 2)
-		g, ctx := errgroup.WithContext(context.Background())
+		if showText {
+			fmt.Printf("🔄 Comparing JFrog CLI versions: %s (baseline: %s)\n", strings.Join(versions, ", "), baseline)
+			fmt.Printf("📝 Command: jf %s\n", strings.Join(jfCommand, " "))
+			if !rules.IsEmpty() {
+				fmt.Printf("🧹 Normalizing: %s\n", rules)
+			}
+			if run.IsolateHome {
+				fmt.Printf("🏠 Each version runs with its own copy of %s\n", utils.JfrogCliHome())
+			}
+			if run.Seed != "" {
+				fmt.Printf("📁 Each version runs in a scratch directory seeded from %s\n", run.Seed)
+			} else if run.Scratch {
+				fmt.Printf("📁 Each version runs in an empty scratch directory\n")
+			}
+			if run.Replay != nil && run.Replay.Recording() {
+				fmt.Printf("📼 Recording HTTP traffic of %s as %s\n", versions[0], run.Replay.Name())
+			} else if run.Replay != nil {
+				fmt.Printf("📼 Replaying HTTP traffic from recording %s\n", run.Replay.Name())
+			}
+			if run.NetDiff {
+				fmt.Printf("🌐 Each version's HTTP calls are traced through a local proxy\n")
+			}
+			fmt.Println()
+		}
 
 		timeout := time.Duration(c.Int("timeout")) * time.Second
-		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
-		defer cancel()
-
-		g.Go(func() error {
-			result, err := executeJFCommand(timeoutCtx, resolved1, jfCommand)
-			results[0] = result
-			return err
-		})
+		results := runCompare(versions, jfCommand, timeout, run)
+		for i := range results {
+			normalizeResult(&results[i], normalizer)
+		}
 
-		g.Go(func() error {
-			result, err := executeJFCommand(timeoutCtx, resolved2, jfCommand)
-			results[1] = result
-			return err
-		})
+		opts := compareOptions(c, channels)
+		comparison := groupResults(strings.Join(jfCommand, " "), baseline, results, opts)
+		if showText {
+			displayComparison(comparison, opts)
+		}
 
-		if err := g.Wait(); err != nil {
-			fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n\n", err)
+		report := buildCompareReport(comparison, opts)
+		if format != FormatText {
+			if err := writeReport(c.String("output"), func(w io.Writer) error {
+				return writeCompareReport(w, report, format)
+			}); err != nil {
+				return err
+			}
 		}
 
-		// Display results
-		displayComparison(results[0], results[1], c.Bool("unified"), c.Bool("no-color"), c.Bool("timing"))
+		if err := emitAnnotations(style, c.String("codequality"), compareAnnotations(report), c.Bool("fail-on-diff")); err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
 
+		if c.Bool("fail-on-diff") && !report.Identical {
+			return cli.Exit(fmt.Sprintf("❌ %d of %d versions differ from baseline %s", len(comparison.Results)-len(comparison.Groups[0].Versions), len(comparison.Results), baseline), 1)
+		}
 		return nil
 	},
 }
 
-func executeJFCommand(ctx context.Context, version string, jfCommand []string) (ExecutionResult, error) {
+// compareFormat validates --format and --output. showText is false when the text report is
+// replaced by a machine-readable one on stdout.
+func compareFormat(c *cli.Context) (string, bool, error) {
+	format := c.String("format")
+	if !slices.Contains([]string{FormatText, FormatJSON, FormatJUnit, FormatMarkdown}, format) {
+		return "", false, fmt.Errorf("Unsupported format '%s' (supported: text, json, junit, markdown)", format)
+	}
+	if format == FormatText && c.String("output") != "" {
+		return "", false, fmt.Errorf("--output needs a report --format: json, junit or markdown")
+	}
+	return format, format == FormatText || c.String("output") != "", nil
+}
+
+// compareBaseline resolves --baseline, which defaults to the first version.
+func compareBaseline(c *cli.Context, versions []string) (string, error) {
+	name := c.String("baseline")
+	if name == "" {
+		return versions[0], nil
+	}
+	baseline, _ := utils.ResolveVersionOrAlias(name)
+	if !slices.Contains(versions, baseline) {
+		return "", fmt.Errorf("Baseline %s is not one of the compared versions", name)
+	}
+	return baseline, nil
+}
+
+func compareOptions(c *cli.Context, channels []string) CompareOptions {
+	return CompareOptions{
+		Unified:  c.Bool("unified"),
+		NoColor:  c.Bool("no-color"),
+		Timing:   c.Bool("timing"),
+		Context:  c.Int("context"),
+		Width:    c.Int("width"),
+		Channels: channels,
+		JSON: JSONDiffOptions{
+			Disabled:         c.Bool("no-json"),
+			IgnoreArrayOrder: c.Bool("ignore-array-order"),
+			IgnorePaths:      c.StringSlice("ignore-path"),
+		},
+	}
+}
+
+// compareRunOptions reads the flags that control where and how versions run.
+func compareRunOptions(c *cli.Context) (RunOptions, error) {
+	run := RunOptions{
+		IsolateHome: c.Bool("isolate-home"),
+		Scratch:     c.Bool("fs-diff") || c.String("seed") != "",
+		NetDiff:     c.Bool("net-diff"),
+	}
+	if seed := c.String("seed"); seed != "" {
+		info, err := os.Stat(seed)
+		if err != nil || !info.IsDir() {
+			return run, fmt.Errorf("seed %s is not a directory", seed)
+		}
+		if run.Seed, err = filepath.Abs(seed); err != nil {
+			return run, err
+		}
+	}
+	return run, nil
+}
+
+// optionalChannels adds the files and network channels when the run collects them and the
+// channels were not chosen explicitly, and rejects them when it doesn't.
+func optionalChannels(channels []string, explicit bool, run RunOptions) ([]string, error) {
+	optional := []struct {
+		channel string
+		enabled bool
+		flags   string
+	}{
+		{ChannelFiles, run.Scratch, "--fs-diff or --seed"},
+		{ChannelNetwork, run.NetDiff, "--net-diff"},
+	}
+	for _, o := range optional {
+		switch {
+		case !o.enabled && slices.Contains(channels, o.channel):
+			return nil, fmt.Errorf("the %s channel needs %s", o.channel, o.flags)
+		case o.enabled && !explicit:
+			channels = append(slices.Clone(channels), o.channel)
+		}
+	}
+	return channels, nil
+}
+
+// proxyFlags reads --replay, --rerecord and --net-diff. The proxy CA is nil unless traffic
+// goes through the proxy, in which case the caller must isolate the CLI home of every run:
+// the CA is trusted through the certs directory of that home.
+func proxyFlags(c *cli.Context) (string, *utils.ProxyCA, error) {
+	name := c.String("replay")
+	if name == "" && c.Bool("rerecord") {
+		return "", nil, fmt.Errorf("--rerecord needs --replay")
+	}
+	if name == "" && !c.Bool("net-diff") {
+		return "", nil, nil
+	}
+	if name != "" {
+		if err := utils.ValidateRecordingName(name); err != nil {
+			return "", nil, err
+		}
+	}
+	ca, err := utils.NewProxyCA()
+	if err != nil {
+		return "", nil, err
+	}
+	return name, ca, nil
+}
+
+// compareAnnotationStyle validates --annotations and --codequality before anything runs, and
+// returns the style to emit, detected from the CI environment when neither is given.
+func compareAnnotationStyle(c *cli.Context) (string, error) {
+	style := c.String("annotations")
+	switch {
+	case style == "" && c.String("codequality") != "":
+		return AnnotationsGitLab, nil
+	case style == "":
+		return detectAnnotations(), nil
+	case style != AnnotationsGitHub && style != AnnotationsGitLab:
+		return "", fmt.Errorf("unsupported annotations '%s' (supported: %s, %s)", style, AnnotationsGitHub, AnnotationsGitLab)
+	case style == AnnotationsGitLab && c.String("codequality") == "":
+		return "", fmt.Errorf("--annotations %s needs --codequality <file> to write the code quality report to", AnnotationsGitLab)
+	}
+	return style, nil
+}
+
+// writeReport writes a report to path, or to stdout when path is empty.
+func writeReport(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("📝 Report written to %s\n", path)
+	return nil
+}
+
+// compareNormalizationRules combines the rules of --profile with the ones given as flags.
+func compareNormalizationRules(c *cli.Context) (utils.NormalizationRules, error) {
+	rules := utils.NormalizationRules{
+		Builtins:    commaSeparated(c.StringSlice("normalize")),
+		Replace:     c.StringSlice("replace"),
+		IgnoreLines: c.StringSlice("ignore-lines"),
+	}
+	if name := c.String("profile"); name != "" {
+		profile, err := utils.LoadCompareProfile(name)
+		if err != nil {
+			return rules, err
+		}
+		rules = profile.Merge(rules)
+	}
+	return rules, nil
+}
+
+// parseChannels validates --channels values, which may also be comma-separated.
+func parseChannels(values []string) ([]string, error) {
+	var channels []string
+	for _, value := range values {
+		for _, channel := range strings.Split(value, ",") {
+			channel = strings.ToLower(strings.TrimSpace(channel))
+			if channel == "" {
+				continue
+			}
+			if !slices.Contains(allChannels, channel) {
+				return nil, fmt.Errorf("unknown channel '%s' (supported: %s)", channel, strings.Join(allChannels, ", "))
+			}
+			if !slices.Contains(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no channels selected (supported: %s)", strings.Join(allChannels, ", "))
+	}
+	return channels, nil
+}
+
+// normalizeResult applies the normalizer to the stdout, stderr and error of a result.
+func normalizeResult(result *ExecutionResult, normalizer *utils.Normalizer) {
+	result.Output = normalizer.Apply(result.Output)
+	result.Stderr = normalizer.Apply(result.Stderr)
+	result.ErrorMsg = normalizer.Apply(result.ErrorMsg)
+	for i := range result.Calls {
+		call := &result.Calls[i]
+		call.Host, call.Path, call.Query = normalizer.Apply(call.Host), normalizer.Apply(call.Path), normalizer.Apply(call.Query)
+	}
+	for i, file := range result.Files {
+		if file.IsText {
+			normalized := normalizer.Apply(file.Text)
+			if normalized != file.Text {
+				// The checksum decides whether files differ, so it must follow the normalized text
+				sum := sha256.Sum256([]byte(normalized))
+				result.Files[i].Text, result.Files[i].SHA256 = normalized, hex.EncodeToString(sum[:])
+			}
+		}
+	}
+}
+
+// resolveCompareVersions resolves the version arguments of compare. Versions and aliases
+// select one version; ranges select every installed release they match.
+func resolveCompareVersions(specs []string) ([]string, error) {
+	installed, err := utils.ListInstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	add := func(version string) {
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+
+	for _, spec := range specs {
+		if !slices.Contains(installed, spec) && utils.IsVersionRange(spec) {
+			versionRange, _ := utils.ParseVersionRange(spec)
+			matched := 0
+			for _, version := range installed {
+				if versionRange.Matches(version) {
+					add(version)
+					matched++
+				}
+			}
+			if matched == 0 {
+				return nil, fmt.Errorf("no installed versions match range %s", spec)
+			}
+			continue
+		}
+
+		resolved, err := utils.ResolveVersionOrAlias(spec)
+		if err != nil {
+			resolved = spec
+		}
+		if err := utils.CheckVersionExists(resolved); err != nil {
+			return nil, fmt.Errorf("version %s (%s) not found: %w", spec, resolved, err)
+		}
+		add(resolved)
+	}
+	return versions, nil
+}
+
+// runCompare executes the command with every version in parallel.
+func runCompare(versions, jfCommand []string, timeout time.Duration, run RunOptions) []ExecutionResult {
+	results := make([]ExecutionResult, len(versions))
+	g, ctx := errgroup.WithContext(context.Background())
+
+	execute := func(i int, version string) error {
+		if run.Slots != nil {
+			run.Slots <- struct{}{}
+			defer func() { <-run.Slots }()
+		}
+		versionRun, cleanup, err := isolateRun(version, run)
+		if err != nil {
+			results[i] = ExecutionResult{Version: version, Command: strings.Join(jfCommand, " "), ExitCode: -1, ErrorMsg: err.Error(), StartTime: time.Now()}
+			return nil
+		}
+		defer cleanup()
+		// Every version gets the whole timeout, including one that waited for the recording run
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		result, err := executeJFCommand(timeoutCtx, version, jfCommand, versionRun)
+		results[i] = result
+		return err
+	}
+
+	start := 0
+	if run.Replay != nil && run.Replay.Recording() {
+		// The first version records the traffic all other versions replay
+		if err := execute(0, versions[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n\n", err)
+		}
+		if err := recordReplay(run.Replay, versions[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n\n", err)
+		}
+		start = 1
+	}
+	for i := start; i < len(versions); i++ {
+		g.Go(func() error { return execute(i, versions[i]) })
+	}
+
+	if err := g.Wait(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n\n", err)
+	}
+	return results
+}
+
+// recordReplay saves the traffic version recorded, so later runs replay it.
+func recordReplay(replay *utils.HTTPReplay, version string) error {
+	if err := replay.Save(version); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "📼 Recorded %d HTTP exchanges of %s as %s\n", replay.Exchanges(), version, replay.Name())
+	return nil
+}
+
+// sameOutput compares outputs structurally when both are JSON and as text otherwise.
+func sameOutput(output1, output2 string, opts CompareOptions) bool {
+	if changes, ok := diffJSONOutputs(output1, output2, opts.JSON); ok {
+		return len(changes) == 0
+	}
+	return strings.TrimSpace(output1) == strings.TrimSpace(output2)
+}
+
+// sameResult reports whether two executions produced the same outcome on the selected
+// channels. A command that timed out or failed to start only matches one that failed the same way.
+func sameResult(a, b ExecutionResult, opts CompareOptions) bool {
+	if opts.compares(ChannelStdout) && !sameOutput(a.Output, b.Output, opts) {
+		return false
+	}
+	if opts.compares(ChannelStderr) && strings.TrimSpace(a.Stderr) != strings.TrimSpace(b.Stderr) {
+		return false
+	}
+	if opts.compares(ChannelExit) && a.ExitCode != b.ExitCode {
+		return false
+	}
+	if opts.compares(ChannelFiles) && len(diffFileTrees(a.Files, b.Files)) > 0 {
+		return false
+	}
+	if opts.compares(ChannelNetwork) && !slices.Equal(a.Calls, b.Calls) {
+		return false
+	}
+	return a.ErrorMsg == b.ErrorMsg
+}
+
+// groupResults groups versions with identical results, the baseline's group first.
+func groupResults(command, baseline string, results []ExecutionResult, opts CompareOptions) Comparison {
+	comparison := Comparison{Command: command, Baseline: baseline, Results: results}
+
+	ordered := slices.Clone(results)
+	slices.SortStableFunc(ordered, func(a, b ExecutionResult) int {
+		switch {
+		case a.Version == baseline:
+			return -1
+		case b.Version == baseline:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, result := range ordered {
+		found := false
+		for i := range comparison.Groups {
+			if sameResult(comparison.Groups[i].Result, result, opts) {
+				comparison.Groups[i].Versions = append(comparison.Groups[i].Versions, result.Version)
+				found = true
+				break
+			}
+		}
+		if !found {
+			comparison.Groups = append(comparison.Groups, OutputGroup{
+				Label:    string(rune('A' + len(comparison.Groups)%26)),
+				Versions: []string{result.Version},
+				Result:   result,
+			})
+		}
+	}
+	return comparison
+}
+
+func executeJFCommand(ctx context.Context, version string, jfCommand []string, run RunOptions) (ExecutionResult, error) {
 	result := ExecutionResult{
 		Version:   version,
 		Command:   strings.Join(jfCommand, " "),
@@ -143,46 +822,85 @@ func executeJFCommand(ctx context.Context, version string, jfCommand []string) (
 
 	binPath := filepath.Join(utils.JfvmVersions, version, utils.BinaryName)
 
+	env := run.Env
+	var session *utils.ProxySession
+	var err error
+	switch {
+	case run.Replay != nil:
+		session, err = run.Replay.Start()
+	case run.NetDiff:
+		session, err = utils.StartProxy(run.ProxyCA)
+	}
+	if err != nil {
+		result.ExitCode = -1
+		result.ErrorMsg = err.Error()
+		return result, nil
+	}
+	if session != nil {
+		env = append(slices.Clone(env), session.Env()...)
+	}
+
 	cmd := exec.CommandContext(ctx, binPath, jfCommand...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	if run.Stdin != "" {
+		cmd.Stdin = strings.NewReader(run.Stdin)
+	}
+	if run.workdir != "" {
+		cmd.Dir = run.workdir
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err = cmd.Run()
 	result.Duration = time.Since(result.StartTime)
+	if session != nil {
+		_ = session.Close()
+		if misses := session.Misses(); len(misses) > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %d requests not in recording %s, first: %s\n", version, len(misses), run.Replay.Name(), misses[0])
+		}
+		if run.NetDiff {
+			result.Calls = session.Calls()
+		}
+	}
+	result.Output = stdout.String()
+	result.Stderr = stderr.String()
+	// Every version has different temporary directories, which must not show up as differences
+	for dir, name := range map[string]string{run.cliHome: "$JFROG_CLI_HOME_DIR", run.workdir: "$WORKDIR"} {
+		if dir != "" {
+			result.Output = strings.ReplaceAll(result.Output, dir, name)
+			result.Stderr = strings.ReplaceAll(result.Stderr, dir, name)
+		}
+	}
+	if run.workdir != "" {
+		files, scanErr := utils.ScanTree(run.workdir)
+		if scanErr != nil {
+			return result, fmt.Errorf("failed to scan working directory of %s: %w", version, scanErr)
+		}
+		result.Files = files
+	}
 
 	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
+		var exitError *exec.ExitError
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			result.ExitCode = -1
+			result.ErrorMsg = fmt.Sprintf("timed out after %s", result.Duration.Round(time.Second))
+		case errors.As(err, &exitError):
 			result.ExitCode = exitError.ExitCode()
-		} else {
+		default:
 			result.ExitCode = 1
-		}
-		result.ErrorMsg = stderr.String()
-	} else {
-		// When command succeeds, combine stdout and stderr for output comparison
-		// Many CLI tools write informational messages to stderr even on success
-		stdoutStr := stdout.String()
-		stderrStr := stderr.String()
-
-		if stdoutStr != "" && stderrStr != "" {
-			result.Output = stdoutStr + "\n" + stderrStr
-		} else if stdoutStr != "" {
-			result.Output = stdoutStr
-		} else {
-			result.Output = stderrStr
+			result.ErrorMsg = err.Error()
 		}
 	}
 
-	// Keep original stdout for cases where only stdout is needed
-	if result.ExitCode == 0 && result.Output == "" {
-		result.Output = stdout.String()
-	}
-
 	return result, nil
 }
 
-func displayComparison(result1, result2 ExecutionResult, unified, noColor, showTiming bool) {
+func displayComparison(comparison Comparison, opts CompareOptions) {
 	// Setup colors
 	var (
 		redColor   = color.New(color.FgRed)
@@ -190,171 +908,207 @@ func displayComparison(result1, result2 ExecutionResult, unified, noColor, showT
 		blueColor  = color.New(color.FgBlue)
 	)
 
-	if noColor {
+	if opts.NoColor {
 		color.NoColor = true
 	}
 
+	exitCode := func(code int) string {
+		if code == 0 {
+			return greenColor.Sprint("✓ 0")
+		}
+		return redColor.Sprintf("✗ %d", code)
+	}
+
 	// Display headers
 	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n")
 	fmt.Printf("🔍 COMPARISON RESULTS\n")
 	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n\n")
 
 	// Display timing information
-	if showTiming {
+	if opts.Timing {
 		fmt.Printf("⏱️  EXECUTION TIMING:\n")
-		fmt.Printf("   Version %s: %v\n", blueColor.Sprint(result1.Version), result1.Duration)
-		fmt.Printf("   Version %s: %v\n", blueColor.Sprint(result2.Version), result2.Duration)
+		for _, result := range comparison.Results {
+			fmt.Printf("   Version %s: %v\n", blueColor.Sprint(result.Version), result.Duration)
+		}
 		fmt.Printf("\n")
 	}
 
-	// Display exit codes if different
-	if result1.ExitCode != result2.ExitCode {
-		fmt.Printf("🚨 EXIT CODE DIFFERENCE:\n")
-		if result1.ExitCode == 0 {
-			fmt.Printf("   %s: %s\n", result1.Version, greenColor.Sprint("✓ 0"))
-		} else {
-			fmt.Printf("   %s: %s\n", result1.Version, redColor.Sprintf("✗ %d", result1.ExitCode))
+	if len(comparison.Groups) == 1 {
+		result := comparison.Groups[0].Result
+		output := strings.TrimSpace(result.Output)
+		fmt.Printf("✅ OUTPUTS ARE IDENTICAL (%s)\n", strings.Join(opts.channels(), ", "))
+		fmt.Printf("📄 Output (%d lines):\n", len(strings.Split(output, "\n")))
+		fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+		fmt.Printf("%s\n", output)
+		if stderr := strings.TrimSpace(result.Stderr); stderr != "" && opts.compares(ChannelStderr) {
+			fmt.Printf("📄 Stderr (%d lines):\n", len(strings.Split(stderr, "\n")))
+			fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+			fmt.Printf("%s\n", stderr)
 		}
-		if result2.ExitCode == 0 {
-			fmt.Printf("   %s: %s\n", result2.Version, greenColor.Sprint("✓ 0"))
-		} else {
-			fmt.Printf("   %s: %s\n", result2.Version, redColor.Sprintf("✗ %d", result2.ExitCode))
+		if len(result.Files) > 0 && opts.compares(ChannelFiles) {
+			fmt.Printf("📁 Working directory (%d entries):\n", len(result.Files))
+			fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+			for _, file := range result.Files {
+				fmt.Printf("%s (%s)\n", file.Path, describeFile(file))
+			}
 		}
-		fmt.Printf("\n")
+		if len(result.Calls) > 0 && opts.compares(ChannelNetwork) {
+			fmt.Printf("🌐 Network calls (%d):\n", len(result.Calls))
+			fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+			fmt.Printf("%s\n", callsText(result.Calls))
+		}
+		return
 	}
 
-	// Display errors if any
-	if result1.ErrorMsg != "" || result2.ErrorMsg != "" {
-		fmt.Printf("🚨 ERROR OUTPUT:\n")
-		if result1.ErrorMsg != "" {
-			fmt.Printf("   %s ERROR:\n%s\n", redColor.Sprint(result1.Version), result1.ErrorMsg)
-		}
-		if result2.ErrorMsg != "" {
-			fmt.Printf("   %s ERROR:\n%s\n", redColor.Sprint(result2.Version), result2.ErrorMsg)
+	// Only worth listing when some versions agree with each other
+	if len(comparison.Groups) < len(comparison.Results) {
+		fmt.Printf("📦 OUTPUT GROUPS:\n")
+		for _, group := range comparison.Groups {
+			fmt.Printf("   [%s] %-40s exit %s\n", group.Label, strings.Join(group.Versions, ", "), exitCode(group.Result.ExitCode))
 		}
 		fmt.Printf("\n")
 	}
 
-	// Compare outputs
-	output1 := strings.TrimSpace(result1.Output)
-	output2 := strings.TrimSpace(result2.Output)
+	base := comparison.Groups[0]
+	for _, group := range comparison.Groups[1:] {
+		baseName, groupName := groupName(base, comparison), groupName(group, comparison)
+		if len(comparison.Groups) > 2 || len(comparison.Groups) < len(comparison.Results) {
+			fmt.Printf("═══ %s ⟷ %s ═══\n\n", blueColor.Sprint(baseName), blueColor.Sprint(groupName))
+		}
 
-	// Commands with different exit codes should never be considered identical
-	// Even if their stdout happens to be the same, they represent different execution results
-	if output1 == output2 && result1.ExitCode == result2.ExitCode && result1.ErrorMsg == result2.ErrorMsg {
-		fmt.Printf("✅ OUTPUTS ARE IDENTICAL\n")
-		fmt.Printf("📄 Output (%d lines):\n", len(strings.Split(output1, "\n")))
-		fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
-		fmt.Printf("%s\n", output1)
-		return
+		result1, result2 := base.Result, group.Result
+
+		// Display exit codes if different
+		if result1.ExitCode != result2.ExitCode && opts.compares(ChannelExit) {
+			fmt.Printf("🚨 EXIT CODE DIFFERENCE:\n")
+			fmt.Printf("   %s: %s\n", baseName, exitCode(result1.ExitCode))
+			fmt.Printf("   %s: %s\n", groupName, exitCode(result2.ExitCode))
+			fmt.Printf("\n")
+		}
+
+		// Display errors running the command, such as timeouts
+		if result1.ErrorMsg != "" || result2.ErrorMsg != "" {
+			fmt.Printf("🚨 EXECUTION ERRORS:\n")
+			if result1.ErrorMsg != "" {
+				fmt.Printf("   %s: %s\n", redColor.Sprint(baseName), result1.ErrorMsg)
+			}
+			if result2.ErrorMsg != "" {
+				fmt.Printf("   %s: %s\n", redColor.Sprint(groupName), result2.ErrorMsg)
+			}
+			fmt.Printf("\n")
+		}
+
+		output1, output2 := strings.TrimSpace(result1.Output), strings.TrimSpace(result2.Output)
+		if opts.compares(ChannelStdout) && !sameOutput(output1, output2, opts) {
+			if changes, ok := diffJSONOutputs(output1, output2, opts.JSON); ok {
+				displayJSONDiff(changes, baseName, groupName)
+			} else {
+				fmt.Printf("📊 STDOUT DIFFERENCES:\n")
+				displayTextDiff(output1, output2, baseName, groupName, opts)
+			}
+			fmt.Printf("\n")
+		}
+
+		stderr1, stderr2 := strings.TrimSpace(result1.Stderr), strings.TrimSpace(result2.Stderr)
+		if opts.compares(ChannelStderr) && stderr1 != stderr2 {
+			fmt.Printf("📊 STDERR DIFFERENCES:\n")
+			displayTextDiff(stderr1, stderr2, baseName, groupName, opts)
+			fmt.Printf("\n")
+		}
+
+		if changes := diffFileTrees(result1.Files, result2.Files); opts.compares(ChannelFiles) && len(changes) > 0 {
+			displayFileChanges(changes, baseName, groupName)
+			fmt.Printf("\n")
+		}
+
+		calls1, calls2 := callsText(result1.Calls), callsText(result2.Calls)
+		if opts.compares(ChannelNetwork) && calls1 != calls2 {
+			fmt.Printf("🌐 NETWORK CALL DIFFERENCES:\n")
+			displayTextDiff(calls1, calls2, baseName, groupName, opts)
+			fmt.Printf("\n")
+		}
 	}
+}
 
-	fmt.Printf("📊 OUTPUT DIFFERENCES:\n")
+// callsText renders HTTP calls one per line, so call sequences can be diffed like text.
+func callsText(calls []utils.HTTPCall) string {
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = call.String()
+	}
+	return strings.Join(lines, "\n")
+}
 
-	if unified {
-		displayUnifiedDiff(output1, output2, result1.Version, result2.Version, noColor)
+func displayTextDiff(output1, output2, version1, version2 string, opts CompareOptions) {
+	if opts.Unified {
+		displayUnifiedDiff(output1, output2, version1, version2, opts)
 	} else {
-		displaySideBySideDiff(output1, output2, result1.Version, result2.Version, noColor)
+		displaySideBySideDiff(output1, output2, version1, version2, opts)
 	}
 }
 
-func displayUnifiedDiff(output1, output2, version1, version2 string, noColor bool) {
-	dmp := diffmatchpatch.New()
-	diffs := dmp.DiffMain(output1, output2, false)
+// groupName names a group in diff headers: its version, or its label and versions when
+// several versions share the output.
+func groupName(group OutputGroup, comparison Comparison) string {
+	if len(group.Versions) == 1 && len(comparison.Groups) == len(comparison.Results) {
+		return group.Versions[0]
+	}
+	return fmt.Sprintf("[%s] %s", group.Label, strings.Join(group.Versions, ", "))
+}
 
+func displayJSONDiff(changes []JSONChange, version1, version2 string) {
 	var (
-		redColor   = color.New(color.FgRed)
-		greenColor = color.New(color.FgGreen)
+		redColor    = color.New(color.FgRed)
+		greenColor  = color.New(color.FgGreen)
+		yellowColor = color.New(color.FgYellow)
 	)
 
+	fmt.Printf("📊 JSON DIFFERENCES (%d):\n", len(changes))
 	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
 	fmt.Printf("%s %s\n", redColor.Sprint("---"), version1)
 	fmt.Printf("%s %s\n", greenColor.Sprint("+++"), version2)
 	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
 
-	for _, diff := range diffs {
-		switch diff.Type {
-		case diffmatchpatch.DiffDelete:
-			if noColor {
-				fmt.Printf("- %s", diff.Text)
-			} else {
-				redColor.Printf("- %s", diff.Text)
-			}
-		case diffmatchpatch.DiffInsert:
-			if noColor {
-				fmt.Printf("+ %s", diff.Text)
-			} else {
-				greenColor.Printf("+ %s", diff.Text)
-			}
-		case diffmatchpatch.DiffEqual:
-			fmt.Printf("  %s", diff.Text)
+	for _, change := range changes {
+		switch change.Kind {
+		case JSONAdded:
+			fmt.Println(greenColor.Sprintf("+ %s: %s", change.Path, formatJSONValue(change.New)))
+		case JSONRemoved:
+			fmt.Println(redColor.Sprintf("- %s: %s", change.Path, formatJSONValue(change.Old)))
+		default:
+			fmt.Printf("%s %s: %s → %s\n", yellowColor.Sprint("~"), change.Path, redColor.Sprint(formatJSONValue(change.Old)), greenColor.Sprint(formatJSONValue(change.New)))
 		}
 	}
 }
 
-func displaySideBySideDiff(output1, output2, version1, version2 string, noColor bool) {
-	lines1 := strings.Split(output1, "\n")
-	lines2 := strings.Split(output2, "\n")
-
-	maxLines := len(lines1)
-	if len(lines2) > maxLines {
-		maxLines = len(lines2)
-	}
-
+func displayUnifiedDiff(output1, output2, version1, version2 string, opts CompareOptions) {
 	var (
-		blueColor  = color.New(color.FgBlue)
-		redColor   = color.New(color.FgRed, color.Bold)
-		greenColor = color.New(color.FgGreen, color.Bold)
+		redColor   = color.New(color.FgRed)
+		greenColor = color.New(color.FgGreen)
+		cyanColor  = color.New(color.FgCyan)
 	)
 
-	// Header
 	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
-	fmt.Printf("%-40s │ %-40s\n", blueColor.Sprintf("%s", version1), blueColor.Sprintf("%s", version2))
+	fmt.Printf("%s %s\n", redColor.Sprint("---"), version1)
+	fmt.Printf("%s %s\n", greenColor.Sprint("+++"), version2)
 	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
 
-	for i := 0; i < maxLines; i++ {
-		line1 := ""
-		line2 := ""
-
-		if i < len(lines1) {
-			line1 = lines1[i]
-		}
-		if i < len(lines2) {
-			line2 = lines2[i]
-		}
-
-		// Truncate long lines for display
-		if len(line1) > 38 {
-			line1 = line1[:35] + "..."
-		}
-		if len(line2) > 38 {
-			line2 = line2[:35] + "..."
-		}
-
-		marker1 := " "
-		marker2 := " "
-
-		if line1 != line2 {
-			if line1 != "" && line2 == "" {
-				marker1 = "-"
-				if !noColor {
-					line1 = redColor.Sprint(line1)
-				}
-			} else if line1 == "" && line2 != "" {
-				marker2 = "+"
-				if !noColor {
-					line2 = greenColor.Sprint(line2)
-				}
-			} else {
-				marker1 = "~"
-				marker2 = "~"
-				if !noColor {
-					line1 = redColor.Sprint(line1)
-					line2 = greenColor.Sprint(line2)
-				}
+	lines := lineDiff(output1, output2)
+	context := opts.Context
+	if context < 0 {
+		context = len(lines)
+	}
+	for _, hunk := range unifiedHunks(lines, context) {
+		fmt.Println(cyanColor.Sprint(hunk.Header()))
+		for _, line := range hunk.Lines {
+			switch line[0] {
+			case lineDelete:
+				fmt.Println(redColor.Sprint(line))
+			case lineInsert:
+				fmt.Println(greenColor.Sprint(line))
+			default:
+				fmt.Println(line)
 			}
 		}
-
-		fmt.Printf("%s%-39s │ %s%-39s\n", marker1, line1, marker2, line2)
 	}
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseChannels(t *testing.T) {
+	channels, err := parseChannels([]string{"stdout, STDERR", "exit,stdout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(channels, []string{ChannelStdout, ChannelStderr, ChannelExit}) {
+		t.Errorf("got %v", channels)
+	}
+	for values, want := range map[string]string{
+		"stdout,logs": "unknown channel 'logs' (supported: stdout, stderr, exit, files, network)",
+		" , ":         "no channels selected (supported: stdout, stderr, exit, files, network)",
+	} {
+		if _, err := parseChannels([]string{values}); err == nil || err.Error() != want {
+			t.Errorf("%q: got %v, want %q", values, err, want)
+		}
+	}
+}
+
+func TestSameResultComparesSelectedChannels(t *testing.T) {
+	base := ExecutionResult{Output: "ok", Stderr: "warning", ExitCode: 0}
+	tests := []struct {
+		name     string
+		other    ExecutionResult
+		channels []string
+		same     bool
+	}{
+		{"stderr differs", ExecutionResult{Output: "ok", Stderr: "deprecated"}, nil, false},
+		{"stderr differs, not compared", ExecutionResult{Output: "ok", Stderr: "deprecated"}, []string{ChannelStdout, ChannelExit}, true},
+		{"exit differs", ExecutionResult{Output: "ok", Stderr: "warning", ExitCode: 2}, nil, false},
+		{"exit differs, not compared", ExecutionResult{Output: "ok", Stderr: "warning", ExitCode: 2}, []string{ChannelStdout, ChannelStderr}, true},
+		{"only whitespace differs", ExecutionResult{Output: "ok\n", Stderr: " warning"}, nil, true},
+		// A run that failed to finish never matches one that did
+		{"timed out", ExecutionResult{Output: "ok", Stderr: "warning", ErrorMsg: "timed out after 30s"}, []string{ChannelStdout}, false},
+	}
+	for _, test := range tests {
+		if same := sameResult(base, test.other, CompareOptions{Channels: test.channels}); same != test.same {
+			t.Errorf("%s: same is %v, want %v", test.name, same, test.same)
+		}
+	}
+}
+
+func TestOptionalChannels(t *testing.T) {
+	channels, err := optionalChannels(defaultChannels, false, RunOptions{Scratch: true, NetDiff: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(channels, []string{ChannelStdout, ChannelStderr, ChannelExit, ChannelFiles, ChannelNetwork}) {
+		t.Errorf("implied channels: got %v", channels)
+	}
+	// Explicit channels are kept as given
+	if channels, _ := optionalChannels([]string{ChannelStdout}, true, RunOptions{Scratch: true}); !slices.Equal(channels, []string{ChannelStdout}) {
+		t.Errorf("explicit channels: got %v", channels)
+	}
+	if _, err := optionalChannels([]string{ChannelNetwork}, true, RunOptions{}); err == nil || err.Error() != "the network channel needs --net-diff" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestExecuteJFCommandSeparatesChannels(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", `echo "out $*"; echo "err" >&2; exit 3`)
+	installTestScript(t, "2.71.0", "exec sleep 5")
+
+	result, err := executeJFCommand(context.Background(), "2.70.0", []string{"rt", "ping"}, RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Output != "out rt ping\n" || result.Stderr != "err\n" || result.ExitCode != 3 || result.ErrorMsg != "" {
+		t.Errorf("result is %+v", result)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, _ = executeJFCommand(ctx, "2.71.0", nil, RunOptions{})
+	if result.ExitCode != -1 || !strings.HasPrefix(result.ErrorMsg, "timed out after") {
+		t.Errorf("timed out run is %+v", result)
+	}
+}
+
+func TestCompareChannelsFlag(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", "echo same")
+	installTestScript(t, "2.71.0", "echo same; echo deprecated >&2; exit 1")
+
+	var err error
+	captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --fail-on-diff 2.70.0 2.71.0 -- rt ping")
+	})
+	if err == nil {
+		t.Error("stderr and exit code differ, but compare reported no difference")
+	}
+	captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --fail-on-diff --channels stdout 2.70.0 2.71.0 -- rt ping")
+	})
+	if err != nil {
+		t.Errorf("stdout is the same, but compare failed: %v", err)
+	}
+	if err := runCommandLine(t, "jfvm compare --channels files 2.70.0 2.71.0 -- rt ping"); err == nil || err.Error() != "the files channel needs --fs-diff or --seed" {
+		t.Errorf("got %v", err)
+	}
+}
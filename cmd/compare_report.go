@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const diffContextLines = 3
+
+// Report formats of compare.
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatJUnit    = "junit"
+	FormatMarkdown = "markdown"
+)
+
+// CI systems compare can annotate.
+const (
+	AnnotationsGitHub = "github"
+	AnnotationsGitLab = "gitlab"
+)
+
+// CompareReport is the machine-readable form of a Comparison.
+type CompareReport struct {
+	Command   string            `json:"command"`
+	Baseline  string            `json:"baseline"`
+	Channels  []string          `json:"channels"`
+	Identical bool              `json:"identical"`
+	Results   []ExecutionResult `json:"results"`
+	Groups    []ReportGroup     `json:"groups"`
+	Diffs     []ReportDiff      `json:"diffs,omitempty"`
+}
+
+type ReportGroup struct {
+	Label    string   `json:"label"`
+	Versions []string `json:"versions"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// ReportDiff describes how one output group differs from the baseline group. Output is
+// stdout; only the channels the comparison selected are diffed.
+type ReportDiff struct {
+	Baseline      []string     `json:"baseline"`
+	Versions      []string     `json:"versions"`
+	Summary       string       `json:"summary"`
+	ExitCodes     [2]int       `json:"exit_codes"`
+	ErrorsDiffer  bool         `json:"errors_differ,omitempty"`
+	OutputHunks   []DiffHunk   `json:"output_hunks,omitempty"`
+	JSONChanges   []JSONChange `json:"json_changes,omitempty"`
+	OutputsDiffer bool         `json:"outputs_differ"`
+	StderrHunks   []DiffHunk   `json:"stderr_hunks,omitempty"`
+	StderrDiffers bool         `json:"stderr_differs"`
+	FileChanges   []FileChange `json:"file_changes,omitempty"`
+	FilesDiffer   bool         `json:"files_differ,omitempty"`
+	CallHunks     []DiffHunk   `json:"call_hunks,omitempty"`
+	CallsDiffer   bool         `json:"calls_differ,omitempty"`
+}
+
+func buildCompareReport(comparison Comparison, opts CompareOptions) CompareReport {
+	report := CompareReport{
+		Command:   comparison.Command,
+		Baseline:  comparison.Baseline,
+		Channels:  opts.channels(),
+		Identical: len(comparison.Groups) == 1,
+		Results:   comparison.Results,
+	}
+	for _, group := range comparison.Groups {
+		report.Groups = append(report.Groups, ReportGroup{Label: group.Label, Versions: group.Versions, ExitCode: group.Result.ExitCode})
+	}
+	if len(comparison.Groups) == 0 {
+		return report
+	}
+
+	base := comparison.Groups[0]
+	for _, group := range comparison.Groups[1:] {
+		diff := ReportDiff{
+			Baseline:     base.Versions,
+			Versions:     group.Versions,
+			ExitCodes:    [2]int{base.Result.ExitCode, group.Result.ExitCode},
+			ErrorsDiffer: base.Result.ErrorMsg != group.Result.ErrorMsg,
+		}
+
+		output1, output2 := strings.TrimSpace(base.Result.Output), strings.TrimSpace(group.Result.Output)
+		if opts.compares(ChannelStdout) {
+			if changes, ok := diffJSONOutputs(output1, output2, opts.JSON); ok {
+				diff.JSONChanges = changes
+				diff.OutputsDiffer = len(changes) > 0
+			} else if output1 != output2 {
+				diff.OutputHunks = unifiedHunks(lineDiff(output1, output2), diffContextLines)
+				diff.OutputsDiffer = true
+			}
+		}
+
+		stderr1, stderr2 := strings.TrimSpace(base.Result.Stderr), strings.TrimSpace(group.Result.Stderr)
+		if opts.compares(ChannelStderr) && stderr1 != stderr2 {
+			diff.StderrHunks = unifiedHunks(lineDiff(stderr1, stderr2), diffContextLines)
+			diff.StderrDiffers = true
+		}
+
+		if opts.compares(ChannelFiles) {
+			diff.FileChanges = diffFileTrees(base.Result.Files, group.Result.Files)
+			diff.FilesDiffer = len(diff.FileChanges) > 0
+		}
+
+		calls1, calls2 := callsText(base.Result.Calls), callsText(group.Result.Calls)
+		if opts.compares(ChannelNetwork) && calls1 != calls2 {
+			diff.CallHunks = unifiedHunks(lineDiff(calls1, calls2), diffContextLines)
+			diff.CallsDiffer = true
+		}
+
+		var parts []string
+		if diff.ExitCodes[0] != diff.ExitCodes[1] && opts.compares(ChannelExit) {
+			parts = append(parts, fmt.Sprintf("exit code %d → %d", diff.ExitCodes[0], diff.ExitCodes[1]))
+		}
+		if diff.OutputsDiffer {
+			if diff.JSONChanges != nil {
+				parts = append(parts, fmt.Sprintf("%d JSON changes", len(diff.JSONChanges)))
+			} else {
+				parts = append(parts, fmt.Sprintf("stdout differs in %d hunks", len(diff.OutputHunks)))
+			}
+		}
+		if diff.StderrDiffers {
+			parts = append(parts, fmt.Sprintf("stderr differs in %d hunks", len(diff.StderrHunks)))
+		}
+		if diff.FilesDiffer {
+			parts = append(parts, fmt.Sprintf("%d file changes", len(diff.FileChanges)))
+		}
+		if diff.CallsDiffer {
+			parts = append(parts, fmt.Sprintf("network calls differ in %d hunks", len(diff.CallHunks)))
+		}
+		if diff.ErrorsDiffer {
+			parts = append(parts, "execution error differs")
+		}
+		diff.Summary = strings.Join(parts, ", ")
+		report.Diffs = append(report.Diffs, diff)
+	}
+	return report
+}
+
+func writeCompareReport(w io.Writer, report CompareReport, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatJUnit:
+		return writeCompareJUnit(w, report)
+	case FormatMarkdown:
+		return writeCompareMarkdown(w, report)
+	default:
+		return fmt.Errorf("unsupported report format '%s' (supported: %s, %s, %s, %s)", format, FormatText, FormatJSON, FormatJUnit, FormatMarkdown)
+	}
+}
+
+// diffText renders a ReportDiff as plain text for JUnit failures and Markdown.
+func diffText(diff ReportDiff) string {
+	var b strings.Builder
+	if len(diff.JSONChanges) > 0 {
+		for _, change := range diff.JSONChanges {
+			switch change.Kind {
+			case JSONAdded:
+				fmt.Fprintf(&b, "+ %s: %s\n", change.Path, formatJSONValue(change.New))
+			case JSONRemoved:
+				fmt.Fprintf(&b, "- %s: %s\n", change.Path, formatJSONValue(change.Old))
+			default:
+				fmt.Fprintf(&b, "~ %s: %s → %s\n", change.Path, formatJSONValue(change.Old), formatJSONValue(change.New))
+			}
+		}
+	}
+	writeHunks := func(hunks []DiffHunk) {
+		for _, hunk := range hunks {
+			b.WriteString(hunk.Header() + "\n")
+			for _, line := range hunk.Lines {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	writeHunks(diff.OutputHunks)
+	if len(diff.StderrHunks) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("stderr:\n")
+		writeHunks(diff.StderrHunks)
+	}
+	if len(diff.FileChanges) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("files:\n")
+		for _, change := range diff.FileChanges {
+			switch change.Kind {
+			case FileAdded:
+				fmt.Fprintf(&b, "+ %s (%s)\n", change.Path, change.Details[0])
+			case FileRemoved:
+				fmt.Fprintf(&b, "- %s (%s)\n", change.Path, change.Details[0])
+			default:
+				fmt.Fprintf(&b, "~ %s\n", change.Path)
+				for _, detail := range change.Details {
+					fmt.Fprintf(&b, "    %s\n", detail)
+				}
+				writeHunks(change.Hunks)
+			}
+		}
+	}
+	if len(diff.CallHunks) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("network calls:\n")
+		writeHunks(diff.CallHunks)
+	}
+	return b.String()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// compareJUnitSuite has one test case per version, which fails when the version's result
+// differs from the baseline.
+func compareJUnitSuite(name string, report CompareReport) junitTestSuite {
+	suite := junitTestSuite{Name: name}
+
+	diffFor := func(version string) *ReportDiff {
+		for i, diff := range report.Diffs {
+			for _, v := range diff.Versions {
+				if v == version {
+					return &report.Diffs[i]
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s vs %s", result.Version, report.Baseline),
+			ClassName: "jf " + report.Command,
+			Time:      result.Duration.Seconds(),
+			SystemOut: result.Output,
+			SystemErr: strings.TrimSpace(result.Stderr + "\n" + result.ErrorMsg),
+		}
+		if result.Version == report.Baseline {
+			testCase.Name = result.Version + " (baseline)"
+		}
+		if diff := diffFor(result.Version); diff != nil {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s differs from %s: %s", result.Version, report.Baseline, diff.Summary),
+				Type:    "difference",
+				Text:    diffText(*diff),
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Time += testCase.Time
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	if len(report.Results) > 0 {
+		suite.Timestamp = report.Results[0].StartTime.Format(time.RFC3339)
+	}
+	return suite
+}
+
+func writeJUnitSuites(w io.Writer, suites []junitTestSuite) error {
+	root := junitTestSuites{Name: "jfvm compare", Suites: suites}
+	for _, suite := range suites {
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Time += suite.Time
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func writeCompareJUnit(w io.Writer, report CompareReport) error {
+	return writeJUnitSuites(w, []junitTestSuite{compareJUnitSuite("jf "+report.Command, report)})
+}
+
+func writeCompareMarkdown(w io.Writer, report CompareReport) error {
+	var b strings.Builder
+	status := "✅ identical"
+	if !report.Identical {
+		status = "❌ different"
+	}
+	fmt.Fprintf(&b, "### `jf %s`: %s\n\n", report.Command, status)
+
+	groupOf := make(map[string]string)
+	for _, group := range report.Groups {
+		for _, version := range group.Versions {
+			groupOf[version] = group.Label
+		}
+	}
+	b.WriteString("| Version | Group | Exit code | Duration |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, result := range report.Results {
+		name := result.Version
+		if name == report.Baseline {
+			name += " (baseline)"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %s |\n", name, groupOf[result.Version], result.ExitCode, formatDuration(result.Duration))
+	}
+
+	for _, diff := range report.Diffs {
+		fmt.Fprintf(&b, "\n#### %s → %s\n\n%s\n", strings.Join(diff.Baseline, ", "), strings.Join(diff.Versions, ", "), diff.Summary)
+		if text := diffText(diff); text != "" {
+			fmt.Fprintf(&b, "\n```diff\n%s```\n", text)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// detectAnnotations picks the CI annotation style from the environment. GitLab reads
+// findings from a file the job declares as an artifact, so it is never detected.
+func detectAnnotations() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return AnnotationsGitHub
+	}
+	return ""
+}
+
+// annotation is one CI finding: a difference between a version and the baseline.
+type annotation struct {
+	Title   string
+	Message string
+}
+
+func compareAnnotations(report CompareReport) []annotation {
+	var annotations []annotation
+	for _, diff := range report.Diffs {
+		annotations = append(annotations, annotation{
+			Title:   "jfvm compare: jf " + report.Command,
+			Message: fmt.Sprintf("%s differs from %s: %s", strings.Join(diff.Versions, ", "), strings.Join(diff.Baseline, ", "), diff.Summary),
+		})
+	}
+	return annotations
+}
+
+// emitAnnotations reports findings the way the CI system shows them: GitHub workflow
+// commands, or a GitLab code quality report written to codeQuality. Workflow commands go to
+// stderr, which the runner reads as well, so reports printed to stdout stay parseable.
+// The style comes from compareAnnotationStyle, which checks it before anything runs.
+func emitAnnotations(style, codeQuality string, annotations []annotation, failing bool) error {
+	switch style {
+	case "":
+		return nil
+	case AnnotationsGitHub:
+		level := "warning"
+		if failing {
+			level = "error"
+		}
+		for _, a := range annotations {
+			fmt.Fprintf(os.Stderr, "::%s title=%s::%s\n", level, escapeGitHubProperty(a.Title), escapeGitHubData(a.Message))
+		}
+		return nil
+	case AnnotationsGitLab:
+		severity := "minor"
+		if failing {
+			severity = "major"
+		}
+		type location struct {
+			Path  string         `json:"path"`
+			Lines map[string]int `json:"lines"`
+		}
+		type issue struct {
+			Description string   `json:"description"`
+			CheckName   string   `json:"check_name"`
+			Fingerprint string   `json:"fingerprint"`
+			Severity    string   `json:"severity"`
+			Location    location `json:"location"`
+		}
+		issues := []issue{}
+		for _, a := range annotations {
+			sum := sha256.Sum256([]byte(a.Title + a.Message))
+			issues = append(issues, issue{
+				Description: a.Title + ": " + a.Message,
+				CheckName:   "jfvm-compare",
+				Fingerprint: hex.EncodeToString(sum[:16]),
+				Severity:    severity,
+				Location:    location{Path: "jfvm", Lines: map[string]int{"begin": 1}},
+			})
+		}
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(codeQuality, data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📝 Wrote %d findings to %s\n", len(issues), codeQuality)
+		return nil
+	default:
+		return fmt.Errorf("unsupported annotations '%s' (supported: %s, %s)", style, AnnotationsGitHub, AnnotationsGitLab)
+	}
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testReport builds the report of a comparison in which 2.71.0 changed its output and exit code.
+func testReport(t *testing.T) CompareReport {
+	t.Helper()
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	results := []ExecutionResult{
+		{Version: "2.70.0", Output: `{"status": "ok", "took": 5}`, Duration: time.Second, StartTime: start},
+		{Version: "2.71.0", Output: `{"status": "fail", "took": 5, "hint": "retry"}`, Stderr: "warning", ExitCode: 1, Duration: 2 * time.Second, StartTime: start},
+		{Version: "2.72.0", Output: `{"took": 5, "status": "ok"}`, Duration: time.Second, StartTime: start},
+	}
+	opts := CompareOptions{}
+	return buildCompareReport(groupResults("rt ping", "2.70.0", results, opts), opts)
+}
+
+func TestBuildCompareReport(t *testing.T) {
+	report := testReport(t)
+	if report.Identical || len(report.Groups) != 2 || len(report.Diffs) != 1 {
+		t.Fatalf("report has %d groups and %d diffs: %+v", len(report.Groups), len(report.Diffs), report)
+	}
+	diff := report.Diffs[0]
+	if want := "exit code 0 → 1, 2 JSON changes, stderr differs in 1 hunks"; diff.Summary != want {
+		t.Errorf("summary is %q, want %q", diff.Summary, want)
+	}
+	if want := "+ $.hint: \"retry\"\n~ $.status: \"ok\" → \"fail\"\n\nstderr:\n@@ -1,0 +1,1 @@\n+warning\n"; diffText(diff) != want {
+		t.Errorf("diff text is %q, want %q", diffText(diff), want)
+	}
+
+	// Channels that aren't compared don't show up in the diff
+	report = buildCompareReport(groupResults("rt ping", "2.70.0", []ExecutionResult{
+		{Version: "2.70.0", Output: "a"},
+		{Version: "2.71.0", Output: "b", ExitCode: 1},
+	}, CompareOptions{Channels: []string{ChannelExit}}), CompareOptions{Channels: []string{ChannelExit}})
+	if len(report.Diffs) != 1 || report.Diffs[0].Summary != "exit code 0 → 1" || report.Diffs[0].OutputsDiffer {
+		t.Errorf("exit-only diff is %+v", report.Diffs)
+	}
+}
+
+func TestWriteCompareJUnit(t *testing.T) {
+	var b strings.Builder
+	if err := writeCompareReport(&b, testReport(t), FormatJUnit); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(b.String()), &suites); err != nil {
+		t.Fatalf("not JUnit XML: %v\n%s", err, b.String())
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Time != 4 || len(suites.Suites) != 1 {
+		t.Fatalf("got %d tests, %d failures in %gs", suites.Tests, suites.Failures, suites.Time)
+	}
+	suite := suites.Suites[0]
+	if suite.Name != "jf rt ping" || suite.Timestamp != "2025-03-01T10:00:00Z" {
+		t.Errorf("suite is %q at %q", suite.Name, suite.Timestamp)
+	}
+	names := []string{"2.70.0 (baseline)", "2.71.0 vs 2.70.0", "2.72.0 vs 2.70.0"}
+	for i, testCase := range suite.Cases {
+		if testCase.Name != names[i] || (testCase.Failure != nil) != (i == 1) {
+			t.Errorf("case %d is %q, failure %+v", i, testCase.Name, testCase.Failure)
+		}
+	}
+	if failure := suite.Cases[1].Failure; failure != nil && !strings.HasPrefix(failure.Message, "2.71.0 differs from 2.70.0: exit code 0 → 1") {
+		t.Errorf("failure message is %q", failure.Message)
+	}
+}
+
+func TestWriteCompareMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := writeCompareReport(&b, testReport(t), FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	markdown := b.String()
+	for _, want := range []string{
+		"### `jf rt ping`: ❌ different\n",
+		"| 2.70.0 (baseline) | A | 0 | ",
+		"| 2.71.0 | B | 1 | ",
+		"| 2.72.0 | A | 0 | ",
+		"#### 2.70.0, 2.72.0 → 2.71.0\n",
+		"```diff\n+ $.hint: \"retry\"\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, markdown)
+		}
+	}
+	if err := writeCompareReport(&b, testReport(t), "html"); err == nil {
+		t.Error("an unknown format was written")
+	}
+}
+
+func TestEmitGitLabCodeQuality(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quality.json")
+	annotations := compareAnnotations(testReport(t))
+	if len(annotations) != 1 || annotations[0].Message != "2.71.0 differs from 2.70.0, 2.72.0: exit code 0 → 1, 2 JSON changes, stderr differs in 1 hunks" {
+		t.Fatalf("annotations are %+v", annotations)
+	}
+
+	read := func() []map[string]any {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var issues []map[string]any
+		if err := json.Unmarshal(data, &issues); err != nil {
+			t.Fatal(err)
+		}
+		return issues
+	}
+	if err := emitAnnotations(AnnotationsGitLab, path, annotations, false); err != nil {
+		t.Fatal(err)
+	}
+	minor := read()
+	if err := emitAnnotations(AnnotationsGitLab, path, annotations, true); err != nil {
+		t.Fatal(err)
+	}
+	major := read()
+	if minor[0]["severity"] != "minor" || major[0]["severity"] != "major" {
+		t.Errorf("severities are %v and %v", minor[0]["severity"], major[0]["severity"])
+	}
+	// GitLab tracks findings across pipelines by fingerprint
+	if minor[0]["fingerprint"] != major[0]["fingerprint"] {
+		t.Error("the fingerprint of the same finding changed")
+	}
+
+	if err := emitAnnotations(AnnotationsGitLab, path, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]" {
+		t.Errorf("a report without findings is %s, want []", data)
+	}
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	if got := escapeGitHubData("100% done\r\nnext: a,b"); got != "100%25 done%0D%0Anext: a,b" {
+		t.Errorf("data escaped as %q", got)
+	}
+	if got := escapeGitHubProperty("jf rt: a,b%\n"); got != "jf rt%3A a%2Cb%25%0A" {
+		t.Errorf("property escaped as %q", got)
+	}
+}
@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// Expectations a suite case can have about how versions compare.
+const (
+	ExpectIdentical = "identical"
+	ExpectDifferent = "different"
+	ExpectAny       = "any"
+)
+
+const defaultSuiteParallel = 4
+
+// CompareSuite is a YAML file of commands to compare across versions.
+type CompareSuite struct {
+	Name string `yaml:"name"`
+	// Parallel is the number of jf processes run at the same time, across cases and versions
+	Parallel int `yaml:"parallel"`
+	// Defaults apply to every case; a case's own settings take precedence
+	Defaults SuiteCase   `yaml:"defaults"`
+	Cases    []SuiteCase `yaml:"cases"`
+}
+
+// SuiteCase is one command of a suite and how its results are compared.
+type SuiteCase struct {
+	Name  string            `yaml:"name"`
+	Args  []string          `yaml:"args"`
+	Env   map[string]string `yaml:"env"`
+	Stdin string            `yaml:"stdin"`
+	// Timeout is in seconds
+	Timeout int `yaml:"timeout"`
+	// Expect is identical (the default), different or any
+	Expect  string `yaml:"expect"`
+	Profile string `yaml:"profile"`
+
+	utils.NormalizationRules `yaml:",inline"`
+
+	Channels         []string `yaml:"channels"`
+	IgnoreArrayOrder bool     `yaml:"ignore_array_order"`
+	IgnorePaths      []string `yaml:"ignore_paths"`
+}
+
+// suiteRun is a case with the suite defaults and command line flags applied.
+type suiteRun struct {
+	Name    string
+	Command []string
+	// Env is the case environment before ${VAR} expansion, which Run.Env holds
+	Env        map[string]string
+	Run        RunOptions
+	Rules      utils.NormalizationRules
+	Timeout    time.Duration
+	Expect     string
+	Normalizer *utils.Normalizer
+	Opts       CompareOptions
+}
+
+// SuiteCaseResult is the outcome of one case.
+type SuiteCaseResult struct {
+	Name   string        `json:"name"`
+	Expect string        `json:"expect"`
+	Passed bool          `json:"passed"`
+	Reason string        `json:"reason,omitempty"`
+	Report CompareReport `json:"report"`
+
+	comparison Comparison
+	opts       CompareOptions
+}
+
+// SuiteReport is the consolidated result of a suite.
+type SuiteReport struct {
+	Suite    string            `json:"suite"`
+	Versions []string          `json:"versions"`
+	Baseline string            `json:"baseline"`
+	Passed   int               `json:"passed"`
+	Failed   int               `json:"failed"`
+	Cases    []SuiteCaseResult `json:"cases"`
+}
+
+// loadCompareSuite reads and validates a suite file.
+func loadCompareSuite(path string) (*CompareSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+	var suite CompareSuite
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&suite); err != nil {
+		return nil, fmt.Errorf("invalid suite %s: %w", path, err)
+	}
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	if len(suite.Cases) == 0 {
+		return nil, fmt.Errorf("suite %s has no cases", path)
+	}
+	seen := make(map[string]bool)
+	for i, suiteCase := range suite.Cases {
+		if suiteCase.Name == "" {
+			return nil, fmt.Errorf("case %d of suite %s has no name", i+1, path)
+		}
+		if seen[suiteCase.Name] {
+			return nil, fmt.Errorf("suite %s has more than one case named '%s'", path, suiteCase.Name)
+		}
+		seen[suiteCase.Name] = true
+		if len(suiteCase.Args) == 0 {
+			return nil, fmt.Errorf("case '%s' has no args", suiteCase.Name)
+		}
+	}
+	return &suite, nil
+}
+
+// prepare resolves one case against the suite defaults and the command line. Flags give
+// the base normalization rules and options, defaults extend them and the case has the last word.
+func (s *CompareSuite) prepare(suiteCase SuiteCase, flagRules utils.NormalizationRules, base CompareOptions, timeout time.Duration) (suiteRun, error) {
+	defaults := s.Defaults
+	run := suiteRun{
+		Name:    suiteCase.Name,
+		Command: suiteCase.Args,
+		Timeout: timeout,
+		Expect:  ExpectIdentical,
+		Opts:    base,
+	}
+
+	run.Env = make(map[string]string)
+	for key, value := range defaults.Env {
+		run.Env[key] = value
+	}
+	for key, value := range suiteCase.Env {
+		run.Env[key] = value
+	}
+	run.Run.Env = expandEnv(run.Env)
+
+	run.Run.Stdin = defaults.Stdin
+	if suiteCase.Stdin != "" {
+		run.Run.Stdin = suiteCase.Stdin
+	}
+	for _, seconds := range []int{defaults.Timeout, suiteCase.Timeout} {
+		if seconds > 0 {
+			run.Timeout = time.Duration(seconds) * time.Second
+		}
+	}
+	for _, expect := range []string{defaults.Expect, suiteCase.Expect} {
+		if expect != "" {
+			run.Expect = expect
+		}
+	}
+	if !slices.Contains([]string{ExpectIdentical, ExpectDifferent, ExpectAny}, run.Expect) {
+		return run, fmt.Errorf("case '%s': unknown expect '%s' (supported: %s, %s, %s)", suiteCase.Name, run.Expect, ExpectIdentical, ExpectDifferent, ExpectAny)
+	}
+
+	rules := flagRules
+	for _, layer := range []SuiteCase{defaults, suiteCase} {
+		if layer.Profile != "" {
+			profile, err := utils.LoadCompareProfile(layer.Profile)
+			if err != nil {
+				return run, fmt.Errorf("case '%s': %w", suiteCase.Name, err)
+			}
+			rules = rules.Merge(profile)
+		}
+		rules = rules.Merge(layer.NormalizationRules)
+	}
+	normalizer, err := rules.Compile()
+	if err != nil {
+		return run, fmt.Errorf("case '%s': %w", suiteCase.Name, err)
+	}
+	run.Rules, run.Normalizer = rules, normalizer
+
+	for _, channels := range [][]string{defaults.Channels, suiteCase.Channels} {
+		if len(channels) == 0 {
+			continue
+		}
+		if run.Opts.Channels, err = parseChannels(channels); err != nil {
+			return run, fmt.Errorf("case '%s': %w", suiteCase.Name, err)
+		}
+	}
+	run.Opts.JSON.IgnoreArrayOrder = base.JSON.IgnoreArrayOrder || defaults.IgnoreArrayOrder || suiteCase.IgnoreArrayOrder
+	run.Opts.JSON.IgnorePaths = slices.Concat(base.JSON.IgnorePaths, defaults.IgnorePaths, suiteCase.IgnorePaths)
+	return run, nil
+}
+
+// expandEnv turns an environment map into KEY=value entries, expanding ${VAR} references
+// in the values. Entries are sorted so runs are reproducible.
+func expandEnv(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var entries []string
+	for _, key := range keys {
+		entries = append(entries, key+"="+os.ExpandEnv(env[key]))
+	}
+	return entries
+}
+
+// runSuiteCase compares one case across the versions and checks it against its expectation.
+func runSuiteCase(run suiteRun, versions []string, baseline string) SuiteCaseResult {
+	results := runCompare(versions, run.Command, run.Timeout, run.Run)
+	for i := range results {
+		normalizeResult(&results[i], run.Normalizer)
+	}
+	comparison := groupResults(strings.Join(run.Command, " "), baseline, results, run.Opts)
+	report := buildCompareReport(comparison, run.Opts)
+
+	result := SuiteCaseResult{Name: run.Name, Expect: run.Expect, Report: report, comparison: comparison, opts: run.Opts}
+	switch run.Expect {
+	case ExpectDifferent:
+		result.Passed = !report.Identical
+		if !result.Passed {
+			result.Reason = "expected versions to differ, but all results are identical"
+		}
+	case ExpectAny:
+		result.Passed = true
+	default:
+		result.Passed = report.Identical
+		if !result.Passed {
+			var reasons []string
+			for _, diff := range report.Diffs {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", strings.Join(diff.Versions, ", "), diff.Summary))
+			}
+			result.Reason = "differs from " + baseline + " (" + strings.Join(reasons, "; ") + ")"
+		}
+	}
+	return result
+}
+
+// runCompareSuite runs every case of a suite with bounded parallelism. It exits with 1 when
+// a case doesn't meet its expectation and with 2 when the suite can't be run.
+func runCompareSuite(c *cli.Context, path string, args []string, flagRules utils.NormalizationRules) error {
+	if slices.Contains(args, "--") {
+		return cli.Exit("A suite takes versions only; the commands come from the suite file", 2)
+	}
+	suite, err := loadCompareSuite(path)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+
+	versions, err := resolveCompareVersions(args)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	if len(versions) < 2 {
+		return cli.Exit("Need at least two versions to compare. Usage: jfvm compare --suite <file> <version1> <version2> [version or range...]", 2)
+	}
+	format, showText, err := compareFormat(c)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	style, err := compareAnnotationStyle(c)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	baseline, err := compareBaseline(c, versions)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	channels, err := parseChannels(c.StringSlice("channels"))
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+
+	run, err := compareRunOptions(c)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	if channels, err = optionalChannels(channels, c.IsSet("channels"), run); err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	replay, ca, err := proxyFlags(c)
+	if err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+	if ca != nil {
+		run.IsolateHome = true
+	}
+
+	base := compareOptions(c, channels)
+	if base.NoColor {
+		color.NoColor = true
+	}
+	// Per-version timings of every case would drown the diffs; the summary shows durations
+	base.Timing = false
+	timeout := time.Duration(c.Int("timeout")) * time.Second
+	runs := make([]suiteRun, len(suite.Cases))
+	for i, suiteCase := range suite.Cases {
+		if runs[i], err = suite.prepare(suiteCase, flagRules, base, timeout); err != nil {
+			return cli.Exit(err.Error(), 2)
+		}
+		// Channels a case picks itself must still be collected by this run
+		if _, err = optionalChannels(runs[i].Opts.Channels, true, run); err != nil {
+			return cli.Exit(fmt.Sprintf("case '%s': %s", suiteCase.Name, err), 2)
+		}
+		runs[i].Run.IsolateHome, runs[i].Run.Scratch, runs[i].Run.Seed = run.IsolateHome, run.Scratch, run.Seed
+		runs[i].Run.NetDiff, runs[i].Run.ProxyCA = run.NetDiff, ca
+		if replay != "" {
+			// Every case has its own recording within the suite's
+			name := replay + "/" + strings.Trim(unsafeNameChars.ReplaceAllString(suiteCase.Name, "-"), "-.")
+			if runs[i].Run.Replay, err = utils.NewHTTPReplay(name, ca, c.Bool("rerecord")); err != nil {
+				return cli.Exit(err.Error(), 2)
+			}
+		}
+	}
+
+	parallel := c.Int("parallel")
+	if parallel <= 0 {
+		parallel = suite.Parallel
+	}
+	if parallel <= 0 {
+		parallel = defaultSuiteParallel
+	}
+
+	if showText {
+		fmt.Printf("🧪 Running suite %s: %d cases across %s (baseline: %s, %d processes at a time)\n\n", suite.Name, len(runs), strings.Join(versions, ", "), baseline, parallel)
+	}
+
+	// Each case runs all versions at once, so the limit applies to processes, not cases
+	slots := make(chan struct{}, parallel)
+	for i := range runs {
+		runs[i].Run.Slots = slots
+	}
+
+	report := SuiteReport{Suite: suite.Name, Versions: versions, Baseline: baseline, Cases: make([]SuiteCaseResult, len(runs))}
+	g, _ := errgroup.WithContext(context.Background())
+	g.SetLimit(parallel)
+	for i, run := range runs {
+		g.Go(func() error {
+			report.Cases[i] = runSuiteCase(run, versions, baseline)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var annotations []annotation
+	for _, result := range report.Cases {
+		if result.Passed {
+			report.Passed++
+			continue
+		}
+		report.Failed++
+		annotations = append(annotations, annotation{Title: "jfvm compare suite " + suite.Name + ": " + result.Name, Message: result.Reason})
+	}
+
+	if showText {
+		displaySuiteReport(report)
+	}
+	if format != FormatText {
+		if err := writeReport(c.String("output"), func(w io.Writer) error {
+			return writeSuiteReport(w, report, format)
+		}); err != nil {
+			return cli.Exit(err.Error(), 2)
+		}
+	}
+	if err := emitAnnotations(style, c.String("codequality"), annotations, true); err != nil {
+		return cli.Exit(err.Error(), 2)
+	}
+
+	if report.Failed > 0 {
+		return cli.Exit(fmt.Sprintf("❌ %d of %d cases failed", report.Failed, len(report.Cases)), 1)
+	}
+	return nil
+}
+
+func displaySuiteReport(report SuiteReport) {
+	var (
+		redColor   = color.New(color.FgRed)
+		greenColor = color.New(color.FgGreen)
+		dimColor   = color.New(color.Faint)
+	)
+
+	width := 0
+	for _, result := range report.Cases {
+		width = max(width, len(result.Name))
+	}
+
+	for _, result := range report.Cases {
+		var slowest time.Duration
+		for _, execution := range result.Report.Results {
+			slowest = max(slowest, execution.Duration)
+		}
+		status := greenColor.Sprint("✅")
+		detail := "identical"
+		switch {
+		case !result.Passed:
+			status = redColor.Sprint("❌")
+			detail = result.Reason
+		case !result.Report.Identical && result.Expect == ExpectDifferent:
+			detail = "differs as expected"
+		case !result.Report.Identical:
+			detail = "differs (not checked)"
+		}
+		fmt.Printf("%s %-*s  %s %s\n", status, width, result.Name, detail, dimColor.Sprintf("(%s)", formatDuration(slowest)))
+	}
+	fmt.Println()
+
+	for _, result := range report.Cases {
+		if result.Passed {
+			continue
+		}
+		fmt.Printf("═══ ❌ %s: jf %s ═══\n", result.Name, result.Report.Command)
+		if result.Expect == ExpectDifferent {
+			fmt.Printf("%s\n\n", result.Reason)
+			continue
+		}
+		displayComparison(result.comparison, result.opts)
+		fmt.Println()
+	}
+
+	summary := fmt.Sprintf("📋 Suite %s: %d passed, %d failed (%d cases, %d versions)", report.Suite, report.Passed, report.Failed, len(report.Cases), len(report.Versions))
+	if report.Failed > 0 {
+		fmt.Println(redColor.Sprint(summary))
+	} else {
+		fmt.Println(greenColor.Sprint(summary))
+	}
+}
+
+func writeSuiteReport(w io.Writer, report SuiteReport, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatJUnit:
+		var suites []junitTestSuite
+		for _, result := range report.Cases {
+			suites = append(suites, suiteJUnitSuite(result))
+		}
+		return writeJUnitSuites(w, suites)
+	case FormatMarkdown:
+		var b strings.Builder
+		fmt.Fprintf(&b, "## Suite %s: %d passed, %d failed\n\n", report.Suite, report.Passed, report.Failed)
+		fmt.Fprintf(&b, "Versions: %s (baseline: %s)\n\n", strings.Join(report.Versions, ", "), report.Baseline)
+		b.WriteString("| Case | Expect | Result |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, result := range report.Cases {
+			status := "✅ passed"
+			if !result.Passed {
+				status = "❌ " + result.Reason
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", result.Name, result.Expect, status)
+		}
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+		for _, result := range report.Cases {
+			if result.Passed {
+				continue
+			}
+			if err := writeCompareMarkdown(w, result.Report); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format '%s' (supported: %s, %s, %s, %s)", format, FormatText, FormatJSON, FormatJUnit, FormatMarkdown)
+	}
+}
+
+// suiteJUnitSuite is the JUnit suite of one case. Versions that differ from the baseline
+// only fail when the case expects identical results.
+func suiteJUnitSuite(result SuiteCaseResult) junitTestSuite {
+	suite := compareJUnitSuite(result.Name, result.Report)
+	if result.Expect == ExpectIdentical {
+		return suite
+	}
+
+	suite.Failures = 0
+	for i := range suite.Cases {
+		suite.Cases[i].Failure = nil
+		if !result.Passed && result.Report.Results[i].Version != result.Report.Baseline {
+			suite.Cases[i].Failure = &junitFailure{Message: result.Reason, Type: "expectation"}
+			suite.Failures++
+		}
+	}
+	return suite
+}
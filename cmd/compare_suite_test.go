@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestCompareSuiteParallelLimitsProcesses(t *testing.T) {
+	useTestRoot(t)
+	dir := t.TempDir()
+	running, peaks := filepath.Join(dir, "running"), filepath.Join(dir, "peaks")
+	if err := os.Mkdir(running, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Every process notes how many processes run while it does
+	script := "touch " + running + "/$$; ls " + running + " | wc -l >> " + peaks + "; sleep 0.2; rm " + running + "/$$; echo ok"
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0"} {
+		installTestScript(t, version, script)
+	}
+	suite := filepath.Join(dir, "suite.yaml")
+	cases := "name: limits\nparallel: 2\ncases:\n"
+	for _, name := range []string{"a", "b", "c"} {
+		cases += "  - name: " + name + "\n    args: [rt, ping]\n"
+	}
+	if err := os.WriteFile(suite, []byte(cases), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm compare --suite "+suite+" 2.70.0 2.71.0 2.72.0"); err != nil {
+			t.Errorf("suite failed: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(peaks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Fields(string(data))
+	if len(lines) != 9 {
+		t.Fatalf("%d processes ran, want 9", len(lines))
+	}
+	for _, line := range lines {
+		if count, _ := strconv.Atoi(line); count > 2 {
+			t.Fatalf("%d processes ran at once with parallel: 2", count)
+		}
+	}
+}
+
+func TestCompareSuiteRejectsChannelsTheRunDoesNotCollect(t *testing.T) {
+	useTestRoot(t)
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	for _, version := range []string{"2.70.0", "2.71.0"} {
+		installTestScript(t, version, "touch "+marker)
+	}
+	for _, tc := range []struct{ yaml, want string }{
+		{"cases:\n  - name: tree\n    args: [rt, ping]\n    channels: [stdout, files]\n", "case 'tree': the files channel needs --fs-diff or --seed"},
+		{"defaults:\n  channels: [network]\ncases:\n  - name: calls\n    args: [rt, ping]\n", "case 'calls': the network channel needs --net-diff"},
+	} {
+		suite := filepath.Join(dir, "suite.yaml")
+		if err := os.WriteFile(suite, []byte("name: channels\n"+tc.yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := runCommandLine(t, "jfvm compare --suite "+suite+" 2.70.0 2.71.0")
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("got %v, want %q", err, tc.want)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("versions ran although the suite was invalid")
+	}
+}
+
+// writeSuite writes a suite file to a temporary directory and returns its path.
+func writeSuite(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "smoke.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCompareSuiteValidates(t *testing.T) {
+	suite, err := loadCompareSuite(writeSuite(t, "cases:\n  - name: ping\n    args: [rt, ping]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suite.Name != "smoke" {
+		t.Errorf("suite without a name is called %q, want the file name", suite.Name)
+	}
+
+	for yaml, want := range map[string]string{
+		"cases: []\n":                    "has no cases",
+		"cases:\n  - args: [rt, ping]\n": "case 1 of suite",
+		"cases:\n  - name: a\n    args: [rt, ping]\n  - name: a\n    args: [-v]\n": "more than one case named 'a'",
+		"cases:\n  - name: a\n": "case 'a' has no args",
+		"cases:\n  - name: a\n    args: [rt, ping]\n    expected: different\n": "field expected not found",
+	} {
+		if _, err := loadCompareSuite(writeSuite(t, yaml)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got %v, want %q", yaml, err, want)
+		}
+	}
+}
+
+func TestSuitePrepareLayersDefaultsAndCases(t *testing.T) {
+	useTestRoot(t)
+	t.Setenv("JFVM_TEST_SERVER", "https://example.jfrog.io")
+	if err := utils.SaveCompareProfile("ci", utils.NormalizationRules{Builtins: []string{"uuids"}}); err != nil {
+		t.Fatal(err)
+	}
+	suite := &CompareSuite{
+		Defaults: SuiteCase{
+			Env:         map[string]string{"JFROG_CLI_LOG_LEVEL": "ERROR", "SERVER": "${JFVM_TEST_SERVER}/artifactory"},
+			Timeout:     10,
+			Expect:      ExpectAny,
+			Profile:     "ci",
+			Channels:    []string{"stdout", "exit"},
+			IgnorePaths: []string{"$.took"},
+		},
+	}
+	suiteCase := SuiteCase{
+		Name:               "search",
+		Args:               []string{"rt", "search"},
+		Env:                map[string]string{"JFROG_CLI_LOG_LEVEL": "DEBUG"},
+		Expect:             ExpectDifferent,
+		NormalizationRules: utils.NormalizationRules{Replace: []string{"a=>b"}},
+		IgnoreArrayOrder:   true,
+		IgnorePaths:        []string{"$.created"},
+	}
+	base := CompareOptions{JSON: JSONDiffOptions{IgnorePaths: []string{"$.meta"}}}
+	run, err := suite.prepare(suiteCase, utils.NormalizationRules{Builtins: []string{"timestamps"}}, base, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"JFROG_CLI_LOG_LEVEL=DEBUG", "SERVER=https://example.jfrog.io/artifactory"}; !slices.Equal(run.Run.Env, want) {
+		t.Errorf("env is %q, want %q", run.Run.Env, want)
+	}
+	if run.Timeout != 10*time.Second || run.Expect != ExpectDifferent {
+		t.Errorf("timeout %s and expect %s", run.Timeout, run.Expect)
+	}
+	if run.Rules.String() != "timestamps, uuids, 1 replace rules" {
+		t.Errorf("rules are %q", run.Rules)
+	}
+	if !slices.Equal(run.Opts.Channels, []string{ChannelStdout, ChannelExit}) || !run.Opts.JSON.IgnoreArrayOrder {
+		t.Errorf("options are %+v", run.Opts)
+	}
+	if want := []string{"$.meta", "$.took", "$.created"}; !slices.Equal(run.Opts.JSON.IgnorePaths, want) {
+		t.Errorf("ignored paths are %q, want %q", run.Opts.JSON.IgnorePaths, want)
+	}
+
+	for _, broken := range []struct {
+		suiteCase SuiteCase
+		want      string
+	}{
+		{SuiteCase{Name: "a", Expect: "same"}, "case 'a': unknown expect 'same' (supported: identical, different, any)"},
+		{SuiteCase{Name: "b", Profile: "nightly"}, "case 'b': normalization profile 'nightly' not found (available: ci)"},
+		{SuiteCase{Name: "c", Channels: []string{"logs"}}, "case 'c': unknown channel 'logs' (supported: stdout, stderr, exit, files, network)"},
+	} {
+		empty := &CompareSuite{}
+		if _, err := empty.prepare(broken.suiteCase, utils.NormalizationRules{}, CompareOptions{}, time.Second); err == nil || err.Error() != broken.want {
+			t.Errorf("got %v, want %q", err, broken.want)
+		}
+	}
+}
+
+func TestCompareSuiteExpectations(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", `if [ "$1" = version ]; then echo 2.70.0; else echo same; fi`)
+	installTestScript(t, "2.71.0", `if [ "$1" = version ]; then echo 2.71.0; else echo same; fi`)
+	suite := writeSuite(t, `name: smoke
+cases:
+  - name: ping
+    args: [rt, ping]
+  - name: version
+    args: [version]
+    expect: different
+  - name: version-any
+    args: [version]
+    expect: any
+  - name: version-same
+    args: [version]
+`)
+
+	var err error
+	stdout := captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --suite "+suite+" --format json 2.70.0 2.71.0")
+	})
+	if err == nil || err.Error() != "❌ 1 of 4 cases failed" {
+		t.Errorf("got %v", err)
+	}
+	var report SuiteReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout)
+	}
+	passed := make(map[string]bool)
+	for _, result := range report.Cases {
+		passed[result.Name] = result.Passed
+	}
+	if want := map[string]bool{"ping": true, "version": true, "version-any": true, "version-same": false}; !maps.Equal(passed, want) {
+		t.Errorf("passed cases are %v, want %v", passed, want)
+	}
+	if reason := report.Cases[3].Reason; reason != "differs from 2.70.0 (2.71.0: stdout differs in 1 hunks)" {
+		t.Errorf("failure reason is %q", reason)
+	}
+
+	if err := runCommandLine(t, "jfvm compare --suite "+suite+" 2.70.0 2.71.0 -- rt ping"); err == nil || err.Error() != "A suite takes versions only; the commands come from the suite file" {
+		t.Errorf("got %v", err)
+	}
+}
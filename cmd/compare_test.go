@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestCompareRegexFlagsKeepCommas(t *testing.T) {
+	useTestRoot(t)
+	err := runCommandLine(t, `jfvm compare --save-profile commas --normalize timestamps,uuids --replace "x{1,3}=>X" --ignore-lines "^a{2,}$"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := utils.LoadCompareProfile("commas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rules.Replace, []string{"x{1,3}=>X"}) {
+		t.Errorf("--replace saved as %q", rules.Replace)
+	}
+	if !slices.Equal(rules.IgnoreLines, []string{"^a{2,}$"}) {
+		t.Errorf("--ignore-lines saved as %q", rules.IgnoreLines)
+	}
+	if !slices.Equal(rules.Builtins, []string{"timestamps", "uuids"}) {
+		t.Errorf("--normalize saved as %q", rules.Builtins)
+	}
+}
+
+func TestCompareReplaceWithCommaInRegex(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", "echo build-xx")
+	installTestScript(t, "2.71.0", "echo build-xxx")
+
+	if err := runCommandLine(t, `jfvm compare --fail-on-diff 2.70.0 2.71.0 -- rt ping`); err == nil {
+		t.Fatal("outputs differ without a replace rule, but compare reported no difference")
+	}
+	if err := runCommandLine(t, `jfvm compare --fail-on-diff --replace "x{1,3}=>X" 2.70.0 2.71.0 -- rt ping`); err != nil {
+		t.Errorf("outputs differ after replacing x{1,3}: %v", err)
+	}
+}
+
+func TestCompareJSONReportWithAnnotations(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITLAB_CI", "true")
+	installTestScript(t, "2.70.0", "echo old")
+	installTestScript(t, "2.71.0", "echo new")
+
+	var err error
+	stdout := captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --format json 2.70.0 2.71.0 -- rt ping")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report CompareReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout)
+	}
+	if report.Identical {
+		t.Error("report says the versions are identical")
+	}
+	if entries, _ := os.ReadDir("."); len(entries) > 0 {
+		t.Errorf("compare wrote %s without --codequality", entries[0].Name())
+	}
+
+	if err := runCommandLine(t, "jfvm compare --format json --output report.json --codequality quality.json 2.70.0 2.71.0 -- rt ping"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("quality.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issues []map[string]any
+	if err := json.Unmarshal(data, &issues); err != nil || len(issues) != 1 {
+		t.Errorf("code quality report has %d issues (%v): %s", len(issues), err, data)
+	}
+}
+
+func TestCompareRecordingRunDoesNotShortenTimeout(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "2.71.0"} {
+		installTestScript(t, version, "sleep 1.2; echo done")
+	}
+
+	// The versions after the recording run must each get the whole timeout
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm compare --fail-on-diff --replay slow --timeout 2 2.70.0 2.71.0 -- rt ping"); err != nil {
+			t.Errorf("results differ: %v", err)
+		}
+	})
+}
+
+func TestCompareRejectsAnnotationsBeforeRunning(t *testing.T) {
+	useTestRoot(t)
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	for _, version := range []string{"2.70.0", "2.71.0"} {
+		installTestScript(t, version, "touch "+marker)
+	}
+	suite := filepath.Join(dir, "suite.yaml")
+	if err := os.WriteFile(suite, []byte("name: ping\ncases:\n  - name: ping\n    args: [rt, ping]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for line, want := range map[string]string{
+		"jfvm compare --annotations azure 2.70.0 2.71.0 -- rt ping":            "unsupported annotations 'azure' (supported: github, gitlab)",
+		"jfvm compare --annotations gitlab 2.70.0 2.71.0 -- rt ping":           "--annotations gitlab needs --codequality <file> to write the code quality report to",
+		"jfvm compare --suite " + suite + " --annotations azure 2.70.0 2.71.0": "unsupported annotations 'azure' (supported: github, gitlab)",
+	} {
+		if err := runCommandLine(t, line); err == nil || err.Error() != want {
+			t.Errorf("%s: got %v, want %q", line, err, want)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("versions ran although the annotations were invalid")
+	}
+}
+
+func TestGroupResultsPutsBaselineFirst(t *testing.T) {
+	results := []ExecutionResult{
+		{Version: "2.70.0", Output: "a"},
+		{Version: "2.71.0", Output: "b"},
+		{Version: "2.72.0", Output: "a\n"},
+		{Version: "2.73.0", Output: "b", ExitCode: 1},
+	}
+	comparison := groupResults("rt ping", "2.71.0", results, CompareOptions{})
+	want := []OutputGroup{
+		{Label: "A", Versions: []string{"2.71.0"}},
+		{Label: "B", Versions: []string{"2.70.0", "2.72.0"}},
+		{Label: "C", Versions: []string{"2.73.0"}},
+	}
+	if len(comparison.Groups) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(comparison.Groups), len(want), comparison.Groups)
+	}
+	for i, group := range comparison.Groups {
+		if group.Label != want[i].Label || !slices.Equal(group.Versions, want[i].Versions) {
+			t.Errorf("group %d is %s %v, want %s %v", i, group.Label, group.Versions, want[i].Label, want[i].Versions)
+		}
+	}
+	// Results keep the order the versions were given in
+	if comparison.Results[0].Version != "2.70.0" {
+		t.Errorf("results were reordered: %+v", comparison.Results)
+	}
+
+	// Without the exit channel, 2.73.0 matches the baseline
+	comparison = groupResults("rt ping", "2.71.0", results, CompareOptions{Channels: []string{ChannelStdout}})
+	if len(comparison.Groups) != 2 || !slices.Equal(comparison.Groups[0].Versions, []string{"2.71.0", "2.73.0"}) {
+		t.Errorf("stdout-only groups: %+v", comparison.Groups)
+	}
+}
+
+func TestResolveCompareVersions(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0"} {
+		installTestVersion(t, version)
+	}
+	if err := utils.WriteAlias("prod", "2.72.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := resolveCompareVersions([]string{"^2.71", "prod", "2.70.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(versions, []string{"2.71.0", "2.72.0", "2.70.0"}) {
+		t.Errorf("got %v", versions)
+	}
+	if _, err := resolveCompareVersions([]string{"2.70.0", "^3"}); err == nil || err.Error() != "no installed versions match range ^3" {
+		t.Errorf("unmatched range: got %v", err)
+	}
+	if _, err := resolveCompareVersions([]string{"2.70.0", "2.99.0"}); err == nil {
+		t.Error("a version that isn't installed was accepted")
+	}
+}
+
+func TestCompareManyVersionsAgainstBaseline(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", "echo old")
+	installTestScript(t, "2.71.0", "echo new")
+	installTestScript(t, "2.72.0", "echo new")
+
+	var err error
+	stdout := captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --no-color --fail-on-diff --baseline 2.71.0 2.70.0 ^2.71 -- rt ping")
+	})
+	if err == nil || err.Error() != "❌ 1 of 3 versions differ from baseline 2.71.0" {
+		t.Errorf("got %v", err)
+	}
+	if !strings.Contains(stdout, "Comparing JFrog CLI versions: 2.70.0, 2.71.0, 2.72.0 (baseline: 2.71.0)") {
+		t.Errorf("unexpected header:\n%s", stdout)
+	}
+
+	for line, want := range map[string]string{
+		"jfvm compare --baseline 2.72.0 2.70.0 2.71.0 -- rt ping": "Baseline 2.72.0 is not one of the compared versions",
+		"jfvm compare 2.70.0 2.70.0 -- rt ping":                   "Need at least two versions to compare. Usage: jfvm compare <version1> <version2> [version or range...] -- <jf-command> [args...]",
+	} {
+		if err := runCommandLine(t, line); err == nil || err.Error() != want {
+			t.Errorf("%s: got %v, want %q", line, err, want)
+		}
+	}
+}
+
+func TestCompareIsolateHome(t *testing.T) {
+	useTestRoot(t)
+	home := t.TempDir()
+	t.Setenv("JFROG_CLI_HOME_DIR", home)
+	config := filepath.Join(home, "jfrog-cli.conf.v6")
+	if err := os.WriteFile(config, []byte("v6"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Both versions migrate the configuration and print where it lives
+	script := `conf="$JFROG_CLI_HOME_DIR/jfrog-cli.conf.v6"; echo "$(cat "$conf") in $conf"; echo migrated > "$conf"`
+	installTestScript(t, "2.70.0", script)
+	installTestScript(t, "2.71.0", script)
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm compare --isolate-home --fail-on-diff 2.70.0 2.71.0 -- config show"); err != nil {
+			t.Errorf("isolated homes differ: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(config); string(data) != "v6" {
+		t.Errorf("the shared configuration was changed to %q", data)
+	}
+}
@@ -34,7 +34,7 @@ func (cd CommandDescription) Format() string {
 
 var Install = CommandDescription{
 	Usage:       "Install a specific JFrog CLI version",
-	Description: "Downloads and installs the specified version of JFrog CLI from JFrog's public release server.",
+	Description: "Downloads and installs the specified version of JFrog CLI from JFrog's public release server. With --from-source, checks out a git ref from a repository URL or local checkout (mirrored under ~/.jfvm/sources), builds it with the local Go toolchain and installs it as src-<ref>, recording the source, ref and commit.",
 	Examples: []Example{
 		{
 			Command:     "jfvm install 2.74.0",
@@ -44,6 +44,14 @@ var Install = CommandDescription{
 			Command:     "jfvm install latest",
 			Description: "Install the latest available version",
 		},
+		{
+			Command:     "jfvm install --from-source https://github.com/jfrog/jfrog-cli.git@master",
+			Description: "Build the current master branch and install it as 'src-master'",
+		},
+		{
+			Command:     "jfvm install --from-source ~/src/jfrog-cli@fix/upload-retries --name upload-fix",
+			Description: "Build a branch of a local checkout as 'upload-fix'",
+		},
 	},
 }
 
@@ -67,6 +75,14 @@ var Use = CommandDescription{
 			Command:     "jfvm use",
 			Description: "Use version from .jfrog-version file",
 		},
+		{
+			Command:     "jfvm use -",
+			Description: "Switch back to the previously active version",
+		},
+		{
+			Command:     "jfvm use --history",
+			Description: "Show recent version switches",
+		},
 	},
 }
 
@@ -82,8 +98,8 @@ var List = CommandDescription{
 }
 
 var Remove = CommandDescription{
-	Usage:       "Remove a specific JFrog CLI version",
-	Description: "Removes a specific version of JFrog CLI from your system.",
+	Usage:       "Remove installed JFrog CLI versions",
+	Description: "Removes versions of JFrog CLI from your system. Versions can be given by name, glob or range, or selected with --older-than. The active version and versions aliases point at are kept unless --force is given, and removal is confirmed interactively unless --yes is given. Removed versions are moved to the trash and can be brought back with 'jfvm restore'; --purge deletes release versions permanently.",
 	Examples: []Example{
 		{
 			Command:     "jfvm remove 2.72.1",
@@ -93,42 +109,82 @@ var Remove = CommandDescription{
 			Command:     "jfvm remove old-dev",
 			Description: "Remove a linked version named 'old-dev'",
 		},
+		{
+			Command:     "jfvm remove --dry-run \"2.6*\"",
+			Description: "Show which 2.6x versions would be removed",
+		},
+		{
+			Command:     "jfvm remove --yes --older-than 2.70",
+			Description: "Remove all versions older than 2.70.0 without asking",
+		},
+		{
+			Command:     "jfvm remove --force 2.73.0",
+			Description: "Remove a version even though it is active or aliases point at it",
+		},
+		{
+			Command:     "jfvm remove --purge 2.60.0",
+			Description: "Delete a release version without keeping it in the trash",
+		},
 	},
 }
 
 var Clear = CommandDescription{
 	Usage:       "Remove all installed JFrog CLI versions",
-	Description: "Removes all installed versions of JFrog CLI. This action cannot be undone.",
+	Description: "Removes all installed versions of JFrog CLI except those matched by --keep. The active version and versions aliases point at are kept unless --force is given, and removal is confirmed interactively unless --yes is given. Removed versions are moved to the trash unless --purge is given.",
 	Examples: []Example{
 		{
 			Command:     "jfvm clear",
 			Description: "Remove all installed versions",
 		},
+		{
+			Command:     "jfvm clear --keep 2.74.0 --keep \"^2.80\"",
+			Description: "Remove everything except 2.74.0 and 2.80+",
+		},
+		{
+			Command:     "jfvm clear --dry-run",
+			Description: "Show what would be removed",
+		},
 	},
 }
 
 var Alias = CommandDescription{
 	Usage:       "Create or manage version aliases",
-	Description: "Defines an alias for a specific version, making it easier to reference commonly used versions.",
+	Description: "Defines an alias for a specific version, making it easier to reference commonly used versions. Alias targets must be installed versions, unless --force is given.",
 	Examples: []Example{
 		{
-			Command:     "jfvm alias dev 2.74.0",
+			Command:     "jfvm alias set dev 2.74.0",
 			Description: "Create alias 'dev' pointing to version 2.74.0",
 		},
 		{
-			Command:     "jfvm alias prod 2.73.0",
+			Command:     "jfvm alias set prod 2.73.0",
 			Description: "Create alias 'prod' pointing to version 2.73.0",
 		},
 		{
-			Command:     "jfvm alias staging 2.77.0",
-			Description: "Create alias 'staging' pointing to specific version",
+			Command:     "jfvm alias set --track ^2.74 --refresh-interval 24h stable",
+			Description: "Create alias 'stable' that follows the newest 2.x release from 2.74 on",
+		},
+		{
+			Command:     "jfvm alias refresh",
+			Description: "Re-resolve all tracking aliases",
+		},
+		{
+			Command:     "jfvm alias rollback stable",
+			Description: "Point 'stable' back at its previous version",
+		},
+		{
+			Command:     "jfvm alias list",
+			Description: "List aliases with their targets and install status",
+		},
+		{
+			Command:     "jfvm alias prune",
+			Description: "Remove aliases that point at versions which are not installed",
 		},
 	},
 }
 
 var Link = CommandDescription{
 	Usage:       "Link a locally built JFrog CLI binary",
-	Description: "Links a locally built jf binary to be used via jfvm. Useful for development and testing custom builds.",
+	Description: "Links a locally built jf binary to be used via jfvm. Useful for development and testing custom builds. The binary must be a JFrog CLI executable for this platform: jfvm runs it with --version and records the version it reports. The binary is copied once by default; --symlink references it in place and --watch re-copies it whenever it changes. With --build, jf is built from a local jfrog-cli checkout and the git commit and dirty state are recorded.",
 	Examples: []Example{
 		{
 			Command:     "jfvm link --from /Users/dev/go/bin/jf --name local-dev",
@@ -138,17 +194,81 @@ var Link = CommandDescription{
 			Command:     "jfvm link --from ./jf --name custom-build",
 			Description: "Link relative path binary as 'custom-build'",
 		},
+		{
+			Command:     "jfvm link --from ~/src/jfrog-cli/jf --name dev --symlink",
+			Description: "Use the build output in place, so every rebuild is picked up",
+		},
+		{
+			Command:     "jfvm link --from ~/src/jfrog-cli/jf --name dev --watch",
+			Description: "Copy the binary again every time it is rebuilt",
+		},
+		{
+			Command:     "jfvm link --build ~/src/jfrog-cli --name my-fork",
+			Description: "Build a local checkout and link the result as 'my-fork'",
+		},
 	},
 }
 
 var Compare = CommandDescription{
 	Usage:       "Compare JFrog CLI command output between versions",
-	Description: "Compare JFrog CLI command output between two versions in parallel with git-like diff visualization. Measures execution time, success rate, and highlights differences.",
+	Description: "Compare JFrog CLI command output between any number of versions in parallel with git-like diff visualization. Versions can be given as versions, aliases or ranges of installed releases. Versions with identical results are grouped, and each distinct group is diffed against the baseline (the first version, or --baseline). Stdout, stderr and the exit code are diffed as separate channels, and --channels selects which of them count. Outputs can be normalized before diffing with built-in normalizers (timestamps, uuids, ids, paths, durations), regex replace rules and ignored line patterns, which can be saved as named profiles. When both outputs are JSON they are diffed by JSON path, optionally ignoring array order and specific paths. For CI, --fail-on-diff exits with code 1 when results differ, --format writes JSON, JUnit or Markdown reports, differences are annotated on GitHub Actions, and --codequality writes them as a GitLab code quality report. With --suite, the commands come from a YAML file of named cases with their own args, env, stdin, normalizers and expectations, run with bounded parallelism into one pass/fail report. --isolate-home runs each version with its own temporary copy of the JFrog CLI home, so config migrations and lock files of one version don't affect the others. --fs-diff runs each version in its own scratch working directory, optionally seeded from --seed, and diffs the files they leave behind. --replay records the HTTP(S) traffic of the first version through a local proxy and replays it to all versions and later runs, so results don't depend on changing server content. --net-diff logs the method, path, query and status of every HTTP call each version makes and diffs the call sequences next to the output. Text diffs are aligned line by line; the side-by-side view fits the terminal width, wraps long lines, highlights changed words and folds unchanged regions to --context lines. Measures execution time, success rate, and highlights differences.",
 	Examples: []Example{
 		{
 			Command:     "jfvm compare 2.74.0 2.73.0 -- --version",
 			Description: "Compare version output between two releases",
 		},
+		{
+			Command:     "jfvm compare prod candidate latest -- config show",
+			Description: "Compare three versions, diffing against prod",
+		},
+		{
+			Command:     "jfvm compare --baseline 2.70.0 \"^2.70\" -- rt ping",
+			Description: "Compare every installed 2.70+ release against 2.70.0",
+		},
+		{
+			Command:     "jfvm compare --normalize all --ignore-lines \"^Trace ID\" 2.74.0 2.73.0 -- rt search \"libs/*.jar\"",
+			Description: "Mask timestamps, IDs, temp paths and durations and drop trace lines before diffing",
+		},
+		{
+			Command:     "jfvm compare --save-profile search --normalize timestamps --replace \"build-[0-9]+=>build-N\"",
+			Description: "Save normalization rules as the profile 'search'",
+		},
+		{
+			Command:     "jfvm compare --profile search 2.74.0 2.73.0 -- rt search \"libs/*.jar\"",
+			Description: "Compare with a saved normalization profile",
+		},
+		{
+			Command:     "jfvm compare --ignore-array-order --ignore-path \"$..created\" 2.74.0 2.73.0 -- rt search \"libs/*.jar\"",
+			Description: "Diff JSON results by path, ignoring result order and creation times",
+		},
+		{
+			Command:     "jfvm compare --suite checks.yaml --format junit --output suite.xml prod candidate",
+			Description: "Run every case of a suite and write one JUnit report",
+		},
+		{
+			Command:     "jfvm compare --isolate-home 2.60.0 2.74.0 -- config show",
+			Description: "Give each version its own copy of the JFrog CLI home",
+		},
+		{
+			Command:     "jfvm compare --seed ./fixtures 2.73.0 2.74.0 -- rt download \"libs/*.jar\" out/",
+			Description: "Diff the files each version downloads into a seeded scratch directory",
+		},
+		{
+			Command:     "jfvm compare --replay search-libs 2.73.0 2.74.0 -- rt search \"libs/*.jar\"",
+			Description: "Give every version the same recorded server responses",
+		},
+		{
+			Command:     "jfvm compare --net-diff 2.73.0 2.74.0 -- rt upload \"build/*.jar\" libs-release/",
+			Description: "Show which REST calls each version makes",
+		},
+		{
+			Command:     "jfvm compare --channels stdout,exit 2.74.0 2.73.0 -- rt search \"libs/*.jar\"",
+			Description: "Ignore differences in log output on stderr",
+		},
+		{
+			Command:     "jfvm compare --fail-on-diff --format junit --output compare.xml prod candidate -- rt ping",
+			Description: "Fail a CI job when the candidate behaves differently and store a JUnit report",
+		},
 		{
 			Command:     "jfvm compare prod dev -- rt ping",
 			Description: "Compare command outputs using aliases",
@@ -157,6 +277,10 @@ var Compare = CommandDescription{
 			Command:     "jfvm compare 2.74.0 2.73.0 -- config show --unified",
 			Description: "Show unified diff format",
 		},
+		{
+			Command:     "jfvm compare --width 160 --context 10 2.74.0 2.73.0 -- config show",
+			Description: "Use a 160-column side-by-side diff with 10 lines of context around changes",
+		},
 		{
 			Command:     "jfvm compare old new -- rt search \"*.jar\" --no-color --timing=false",
 			Description: "Disable colored output and timing",
@@ -166,7 +290,7 @@ var Compare = CommandDescription{
 
 var Benchmark = CommandDescription{
 	Usage:       "Benchmark JFrog CLI command performance across versions",
-	Description: "Run performance benchmarks for JFrog CLI commands across multiple versions. Measures execution time, success rate, and provides statistical analysis.",
+	Description: "Run performance benchmarks for JFrog CLI commands across multiple versions. Measures execution time, success rate, and provides statistical analysis. --isolate-home gives each version its own temporary copy of the JFrog CLI home. --replay serves the HTTP traffic of every run from a recording.",
 	Examples: []Example{
 		{
 			Command:     "jfvm benchmark 2.74.0,2.73.0,2.72.0 -- --version",
@@ -221,3 +345,162 @@ var History = CommandDescription{
 		},
 	},
 }
+
+var Doctor = CommandDescription{
+	Usage:       "Diagnose and repair jfvm installation problems",
+	Description: "Checks the shim placement and PATH order, shim/jfvm version skew, the active version, installed binaries, aliases, the .jfrog-version file, the history file and directory permissions. Each finding is explained, and --fix repairs what can be repaired safely.",
+	Examples: []Example{
+		{
+			Command:     "jfvm doctor",
+			Description: "Report installation problems",
+		},
+		{
+			Command:     "jfvm doctor --fix",
+			Description: "Report and repair installation problems",
+		},
+	},
+}
+
+var Setup = CommandDescription{
+	Usage:       "Install the jf shim and add it to your shell's PATH",
+	Description: "Places the jf shim into ~/.jfvm/shim and adds it to PATH in the profile of the detected shell (bash, zsh, fish or sh). Profiles are edited inside a marked block, so running setup again is safe. Use --uninstall to remove the shim and the block from every profile.",
+	Examples: []Example{
+		{
+			Command:     "jfvm setup",
+			Description: "Install the shim and configure the current shell",
+		},
+		{
+			Command:     "jfvm setup --shell bash --shell fish",
+			Description: "Configure specific shells",
+		},
+		{
+			Command:     "jfvm setup --skip-shell",
+			Description: "Only install the shim",
+		},
+		{
+			Command:     "jfvm setup --uninstall",
+			Description: "Remove the shim and the PATH configuration",
+		},
+	},
+}
+
+var Sync = CommandDescription{
+	Usage:       "Install and align everything the project manifest declares",
+	Description: "Reads .jfvm.yaml from the current directory or its parents, installs the required version (or the newest release matching its range) and every project alias target, verifies declared checksums, activates the required version and installs required jf plugins at their pinned versions. Binaries that fail their checksum are removed. Project aliases take precedence over personal aliases inside the project tree.",
+	Examples: []Example{
+		{
+			Command:     "jfvm sync",
+			Description: "Align this machine with the project manifest",
+		},
+		{
+			Command:     "jfvm sync --no-use",
+			Description: "Install everything without switching the active version",
+		},
+	},
+}
+
+var Rollback = CommandDescription{
+	Usage:       "Restore an earlier active version from the switch journal",
+	Description: "Every version switch is recorded in a journal with its time, previous and new version and what caused it. Rollback N re-activates the version that was active before the N-th most recent switch, as numbered by 'jfvm use --history'.",
+	Examples: []Example{
+		{
+			Command:     "jfvm rollback",
+			Description: "Undo the most recent switch (same as 'jfvm use -')",
+		},
+		{
+			Command:     "jfvm rollback --reason \"use latest broke the release pipeline\" 3",
+			Description: "Restore the version that was active before the third most recent switch",
+		},
+	},
+}
+
+var Prune = CommandDescription{
+	Usage:       "Remove installed versions that are no longer used",
+	Description: "Keeps the current version, aliased versions, versions referenced by known .jfrog-version and .jfvm.yaml files, versions used within --days according to history, the --keep-latest newest releases and linked builds. Everything else is moved to the trash, or deleted with --purge, and the reclaimed disk space is reported.",
+	Examples: []Example{
+		{
+			Command:     "jfvm prune --dry-run",
+			Description: "Show what would be removed",
+		},
+		{
+			Command:     "jfvm prune --days 14 --keep-latest 3",
+			Description: "Keep versions used in the last two weeks and the three newest releases",
+		},
+	},
+}
+
+var Du = CommandDescription{
+	Usage:       "Show disk usage of installed versions",
+	Description: "Reports the size of every installed version and the total, counting binaries shared through hard links once. With --dedup, byte-identical binaries across version directories (including linked builds) are replaced with hard links to a single copy. Newly installed and linked versions are deduplicated automatically.",
+	Examples: []Example{
+		{
+			Command:     "jfvm du",
+			Description: "Show per-version and total disk usage",
+		},
+		{
+			Command:     "jfvm du --dedup --dry-run",
+			Description: "Show which identical binaries would be stored once",
+		},
+		{
+			Command:     "jfvm du --dedup",
+			Description: "Store identical binaries once",
+		},
+	},
+}
+
+var Trash = CommandDescription{
+	Usage:       "Manage removed versions kept for recovery",
+	Description: "Versions removed with remove, clear or prune are moved to ~/.jfvm/trash. Release versions are deleted automatically after 14 days (set JFVM_TRASH_DAYS to change this); linked builds cannot be re-downloaded and stay until the trash is emptied with --include-linked.",
+	Examples: []Example{
+		{
+			Command:     "jfvm trash list",
+			Description: "Show trashed versions and when they expire",
+		},
+		{
+			Command:     "jfvm trash empty",
+			Description: "Permanently delete trashed release versions",
+		},
+		{
+			Command:     "jfvm trash empty --include-linked --yes",
+			Description: "Permanently delete everything in the trash without asking",
+		},
+	},
+}
+
+var Restore = CommandDescription{
+	Usage:       "Restore a removed version from the trash",
+	Description: "Moves the most recently removed copy of a version back into place. Aliases and project files that point at it work again immediately.",
+	Examples: []Example{
+		{
+			Command:     "jfvm restore my-build",
+			Description: "Bring back a linked build removed by mistake",
+		},
+	},
+}
+
+var Snapshot = CommandDescription{
+	Usage:       "Record command results as golden snapshots and verify versions against them",
+	Description: "Snapshots store the normalized stdout, stderr and exit code of a command under ~/.jfvm/snapshots, together with how it was run and compared, so later versions can be checked even when the recorded version is no longer installed. Record single commands or every case of a compare suite (stored as <suite>/<case>). verify re-runs the snapshots with a version and diffs the results with the compare renderer; --update accepts the new results. verify exits with 1 when a snapshot doesn't match.",
+	Examples: []Example{
+		{
+			Command:     "jfvm snapshot record --normalize all 2.70.0 -- rt search \"libs/*.jar\"",
+			Description: "Record the search results of 2.70.0 as the snapshot rt-search-libs-jar",
+		},
+		{
+			Command:     "jfvm snapshot record --suite checks.yaml 2.70.0",
+			Description: "Record every case of a compare suite, whose cases give their jf arguments as args",
+		},
+		{
+			Command:     "jfvm snapshot verify 2.74.0",
+			Description: "Check 2.74.0 against every snapshot",
+		},
+		{
+			Command:     "jfvm snapshot verify --update 2.74.0 upgrade-checks",
+			Description: "Accept the results of 2.74.0 for the upgrade-checks suite",
+		},
+		{
+			Command:     "jfvm snapshot list",
+			Description: "List recorded snapshots",
+		},
+	},
+}
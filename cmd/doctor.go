@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"debug/buildinfo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
+	"github.com/urfave/cli/v2"
+)
+
+type DoctorStatus int
+
+const (
+	DoctorOK DoctorStatus = iota
+	DoctorWarning
+	DoctorError
+)
+
+// DoctorFinding is the outcome of a single doctor check. Fix is nil when the
+// problem cannot be repaired automatically.
+type DoctorFinding struct {
+	Check   string
+	Status  DoctorStatus
+	Message string
+	Hint    string
+	Fix     func() error
+}
+
+var Doctor = &cli.Command{
+	Name:        "doctor",
+	Usage:       descriptions.Doctor.Usage,
+	Description: descriptions.Doctor.Format(),
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Repair the problems that can be fixed safely",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output",
+			Value: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("no-color") {
+			color.NoColor = true
+		}
+
+		var findings []DoctorFinding
+		for _, check := range doctorChecks {
+			findings = append(findings, check()...)
+		}
+
+		remaining := displayDoctorFindings(findings, c.Bool("fix"))
+		if remaining > 0 {
+			return cli.Exit("", 1)
+		}
+		return nil
+	},
+}
+
+var doctorChecks = []func() []DoctorFinding{
+	checkPermissions,
+	checkShimPlacement,
+	checkPathOrder,
+	checkShimVersion,
+	checkActiveVersion,
+	checkInstalledBinaries,
+	checkAliases,
+	checkProjectFile,
+	checkProjectManifest,
+	checkHistoryFile,
+}
+
+func displayDoctorFindings(findings []DoctorFinding, fix bool) int {
+	var (
+		greenColor  = color.New(color.FgGreen)
+		yellowColor = color.New(color.FgYellow)
+		redColor    = color.New(color.FgRed)
+		blueColor   = color.New(color.FgBlue)
+	)
+
+	fmt.Printf("🩺 JFVM DOCTOR\n")
+	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n\n")
+
+	warnings, failures, fixed := 0, 0, 0
+	for _, finding := range findings {
+		switch finding.Status {
+		case DoctorOK:
+			fmt.Printf("%s %s: %s\n", greenColor.Sprint("✅"), finding.Check, finding.Message)
+			continue
+		case DoctorWarning:
+			fmt.Printf("%s %s: %s\n", yellowColor.Sprint("⚠️ "), finding.Check, finding.Message)
+		case DoctorError:
+			fmt.Printf("%s %s: %s\n", redColor.Sprint("❌"), finding.Check, finding.Message)
+		}
+
+		if fix && finding.Fix != nil {
+			if err := finding.Fix(); err != nil {
+				fmt.Printf("   %s %v\n", redColor.Sprint("🔧 fix failed:"), err)
+			} else {
+				fmt.Printf("   %s\n", greenColor.Sprint("🔧 fixed"))
+				fixed++
+				continue
+			}
+		}
+
+		if finding.Hint != "" {
+			fmt.Printf("   ↳ %s\n", blueColor.Sprint(finding.Hint))
+		}
+		if !fix && finding.Fix != nil {
+			fmt.Printf("   ↳ %s\n", blueColor.Sprint("can be repaired with 'jfvm doctor --fix'"))
+		}
+
+		if finding.Status == DoctorWarning {
+			warnings++
+		} else {
+			failures++
+		}
+	}
+
+	fmt.Printf("\n─────────────────────────────────────────────────────────────────────────────────────\n")
+	fmt.Printf("📋 %d findings, %s, %s", len(findings),
+		yellowColor.Sprintf("%d warnings", warnings),
+		redColor.Sprintf("%d errors", failures))
+	if fix {
+		fmt.Printf(", %s", greenColor.Sprintf("%d fixed", fixed))
+	}
+	fmt.Printf("\n")
+
+	return failures
+}
+
+func checkPermissions() []DoctorFinding {
+	var findings []DoctorFinding
+	for _, dir := range []string{utils.JfvmRoot, utils.JfvmVersions, utils.JfvmAliases, utils.JfvmShim} {
+		dir := dir
+		finding := DoctorFinding{Check: "permissions", Status: DoctorOK, Message: fmt.Sprintf("%s is writable", dir)}
+
+		info, err := os.Stat(dir)
+		switch {
+		case os.IsNotExist(err):
+			finding.Status = DoctorWarning
+			finding.Message = fmt.Sprintf("%s does not exist", dir)
+			finding.Fix = func() error { return os.MkdirAll(dir, 0755) }
+		case err != nil:
+			finding.Status = DoctorError
+			finding.Message = fmt.Sprintf("cannot access %s: %v", dir, err)
+		case !info.IsDir():
+			finding.Status = DoctorError
+			finding.Message = fmt.Sprintf("%s is not a directory", dir)
+			finding.Hint = "move the file out of the way so jfvm can create the directory"
+		default:
+			if err := checkWritable(dir); err != nil {
+				finding.Status = DoctorError
+				finding.Message = fmt.Sprintf("%s is not writable: %v", dir, err)
+				finding.Hint = fmt.Sprintf("run 'chmod u+w %s' or fix the directory owner", dir)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func checkWritable(dir string) error {
+	probe, err := os.CreateTemp(dir, ".jfvm-doctor-*")
+	if err != nil {
+		return err
+	}
+	_ = probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func checkShimPlacement() []DoctorFinding {
+	shimPath := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	finding := DoctorFinding{Check: "shim", Status: DoctorOK, Message: fmt.Sprintf("installed at %s", shimPath)}
+
+	info, err := os.Stat(shimPath)
+	switch {
+	case os.IsNotExist(err):
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("no shim found at %s", shimPath)
+		finding.Hint = "run 'jfvm setup' to install the shim"
+		finding.Fix = shimFix()
+	case err != nil:
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("cannot access %s: %v", shimPath, err)
+	case !info.Mode().IsRegular():
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is not a regular file", shimPath)
+	case info.Size() == 0:
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is empty", shimPath)
+		finding.Hint = "run 'jfvm setup' to reinstall the shim"
+		finding.Fix = shimFix()
+	case info.Mode().Perm()&0111 == 0:
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is not executable", shimPath)
+		finding.Fix = func() error { return os.Chmod(shimPath, 0755) }
+	}
+	return []DoctorFinding{finding}
+}
+
+// shimFix reinstalls the shim when this jfvm has one to install.
+func shimFix() func() error {
+	if _, _, err := findShimSource(""); err != nil {
+		return nil
+	}
+	return func() error { return installShim("") }
+}
+
+func checkPathOrder() []DoctorFinding {
+	shimPath := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	finding := DoctorFinding{Check: "PATH", Status: DoctorOK, Message: fmt.Sprintf("'%s' resolves to the jfvm shim", utils.BinaryName)}
+
+	inPath := false
+	var shadowing []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(dir) == filepath.Clean(utils.JfvmShim) {
+			inPath = true
+			break
+		}
+		candidate := filepath.Join(dir, utils.BinaryName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode().Perm()&0111 != 0 {
+			shadowing = append(shadowing, candidate)
+		}
+	}
+
+	switch {
+	case !inPath:
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is not on PATH", utils.JfvmShim)
+		finding.Hint = "run 'jfvm setup' to add it to your shell profile, then restart the shell"
+	case len(shadowing) > 0:
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("'%s' is shadowed by %s, which comes before the shim on PATH", utils.BinaryName, strings.Join(shadowing, ", "))
+		finding.Hint = fmt.Sprintf("move %s to the front of PATH or remove the other binaries", utils.JfvmShim)
+	default:
+		if resolved, err := exec.LookPath(utils.BinaryName); err == nil && filepath.Clean(resolved) != filepath.Clean(shimPath) {
+			finding.Status = DoctorWarning
+			finding.Message = fmt.Sprintf("'%s' resolves to %s instead of %s", utils.BinaryName, resolved, shimPath)
+		}
+	}
+	return []DoctorFinding{finding}
+}
+
+func checkShimVersion() []DoctorFinding {
+	shimPath := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	finding := DoctorFinding{Check: "shim version", Status: DoctorOK}
+	if _, err := os.Stat(shimPath); err != nil {
+		// Already reported by the shim placement check
+		return nil
+	}
+
+	shimInfo, err := buildinfo.ReadFile(shimPath)
+	if err != nil {
+		finding.Status = DoctorWarning
+		finding.Message = fmt.Sprintf("cannot read build information from %s: %v", shimPath, err)
+		return []DoctorFinding{finding}
+	}
+	selfInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		finding.Message = "jfvm was built without build information, skipping"
+		return []DoctorFinding{finding}
+	}
+
+	shimBuild, selfBuild := describeBuild(shimInfo), describeBuild(selfInfo)
+	finding.Message = fmt.Sprintf("shim and jfvm were built from %s", selfBuild)
+	if shimBuild != selfBuild {
+		finding.Status = DoctorWarning
+		finding.Message = fmt.Sprintf("shim was built from %s but jfvm from %s", shimBuild, selfBuild)
+		finding.Hint = "run 'jfvm setup' to reinstall the shim that matches this jfvm"
+		finding.Fix = shimFix()
+	}
+	return []DoctorFinding{finding}
+}
+
+// describeBuild identifies a binary by its module version and, when available, its VCS revision.
+func describeBuild(info *debug.BuildInfo) string {
+	build := info.Main.Version
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			build = fmt.Sprintf("%s (%s)", build, setting.Value[:12])
+		}
+	}
+	return build
+}
+
+func checkActiveVersion() []DoctorFinding {
+	finding := DoctorFinding{Check: "active version", Status: DoctorOK}
+
+	data, err := os.ReadFile(utils.JfvmConfig)
+	if os.IsNotExist(err) {
+		finding.Status = DoctorWarning
+		finding.Message = "no version is selected"
+		finding.Hint = "run 'jfvm use <version>'"
+		return []DoctorFinding{finding}
+	}
+	if err != nil {
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("cannot read %s: %v", utils.JfvmConfig, err)
+		return []DoctorFinding{finding}
+	}
+
+	version := strings.TrimSpace(string(data))
+	if version == "" {
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is empty", utils.JfvmConfig)
+		finding.Hint = "run 'jfvm use <version>'"
+		return []DoctorFinding{finding}
+	}
+
+	finding.Message = fmt.Sprintf("%s is installed", version)
+	if err := utils.CheckVersionExists(version); err != nil {
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("config points at %s, which is not usable: %v", version, err)
+		finding.Hint = fmt.Sprintf("run 'jfvm use <version>' or 'jfvm install %s'", version)
+		if utils.IsReleaseVersion(version) {
+			finding.Fix = func() error { return internal.DownloadAndInstall(version) }
+		}
+	}
+	return []DoctorFinding{finding}
+}
+
+func checkInstalledBinaries() []DoctorFinding {
+	entries, err := os.ReadDir(utils.JfvmVersions)
+	if err != nil {
+		return nil
+	}
+
+	var findings []DoctorFinding
+	broken := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version := entry.Name()
+		versionDir := filepath.Join(utils.JfvmVersions, version)
+		binPath := filepath.Join(versionDir, utils.BinaryName)
+
+		finding := DoctorFinding{Check: "installed versions", Status: DoctorError}
+		reinstall := func() error {
+			if utils.IsReleaseVersion(version) {
+				return internal.DownloadAndInstall(version)
+			}
+			_, err := utils.MoveToTrash(version)
+			return err
+		}
+		reinstallHint := ""
+		if !utils.IsReleaseVersion(version) {
+			reinstallHint = "the fix moves this linked version to the trash; re-link it with 'jfvm link'"
+		}
+
+		info, err := os.Stat(binPath)
+		if link, lerr := os.Lstat(binPath); lerr == nil && link.Mode()&os.ModeSymlink != 0 && os.IsNotExist(err) {
+			target, _ := os.Readlink(binPath)
+			findings = append(findings, DoctorFinding{
+				Check:   "installed versions",
+				Status:  DoctorError,
+				Message: fmt.Sprintf("%s is a symlink to %s, which does not exist", version, target),
+				Hint:    "rebuild it, or re-link the version with 'jfvm link --symlink'",
+			})
+			broken++
+			continue
+		}
+		switch {
+		case os.IsNotExist(err):
+			finding.Message = fmt.Sprintf("%s has no %s binary", version, utils.BinaryName)
+			finding.Hint = reinstallHint
+			finding.Fix = reinstall
+		case err != nil:
+			finding.Message = fmt.Sprintf("cannot access %s: %v", binPath, err)
+		case utils.IsShimPath(binPath):
+			finding.Message = fmt.Sprintf("%s is the jfvm shim, which would run itself forever", version)
+			finding.Hint = reinstallHint
+			finding.Fix = reinstall
+		case info.Size() == 0:
+			finding.Message = fmt.Sprintf("%s has an empty binary, probably from a failed download", version)
+			finding.Hint = reinstallHint
+			finding.Fix = reinstall
+		case info.Mode().Perm()&0111 == 0:
+			finding.Message = fmt.Sprintf("%s binary is not executable", version)
+			finding.Fix = func() error { return os.Chmod(binPath, 0755) }
+		default:
+			continue
+		}
+
+		findings = append(findings, finding)
+		broken++
+	}
+
+	if broken == 0 {
+		findings = append(findings, DoctorFinding{
+			Check:   "installed versions",
+			Status:  DoctorOK,
+			Message: fmt.Sprintf("%d versions installed, all binaries look valid", len(entries)),
+		})
+	}
+	return findings
+}
+
+func checkAliases() []DoctorFinding {
+	entries, err := os.ReadDir(utils.JfvmAliases)
+	if err != nil {
+		return nil
+	}
+
+	var findings []DoctorFinding
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		alias := entry.Name()
+		version, err := utils.ResolvePersonalAlias(alias)
+		if err != nil {
+			findings = append(findings, DoctorFinding{
+				Check:   "aliases",
+				Status:  DoctorWarning,
+				Message: fmt.Sprintf("cannot read alias '%s': %v", alias, err),
+			})
+			continue
+		}
+		if err := utils.CheckVersionExists(version); err != nil {
+			findings = append(findings, DoctorFinding{
+				Check:   "aliases",
+				Status:  DoctorWarning,
+				Message: fmt.Sprintf("alias '%s' points at %s, which is not installed", alias, version),
+				Hint:    fmt.Sprintf("run 'jfvm install %s' or 'jfvm alias remove %s'", version, alias),
+			})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, DoctorFinding{
+			Check:   "aliases",
+			Status:  DoctorOK,
+			Message: fmt.Sprintf("%d aliases, all point at installed versions", len(entries)),
+		})
+	}
+	return findings
+}
+
+func checkProjectFile() []DoctorFinding {
+	data, err := os.ReadFile(utils.ProjectFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	finding := DoctorFinding{Check: utils.ProjectFile, Status: DoctorOK}
+	if err != nil {
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("cannot read %s: %v", utils.ProjectFile, err)
+		return []DoctorFinding{finding}
+	}
+
+	requested := strings.TrimSpace(string(data))
+	if requested == "" {
+		finding.Status = DoctorError
+		finding.Message = fmt.Sprintf("%s is empty", utils.ProjectFile)
+		return []DoctorFinding{finding}
+	}
+
+	version, _ := utils.ResolveVersionOrAlias(requested)
+	finding.Message = fmt.Sprintf("requests %s, which is installed", requested)
+	if err := utils.CheckVersionExists(version); err != nil {
+		finding.Status = DoctorWarning
+		finding.Message = fmt.Sprintf("requests %s, which is not installed", requested)
+		finding.Hint = "run 'jfvm use' in this directory to install it"
+		if utils.IsReleaseVersion(version) {
+			finding.Fix = func() error { return internal.DownloadAndInstall(version) }
+		}
+	}
+	return []DoctorFinding{finding}
+}
+
+func checkProjectManifest() []DoctorFinding {
+	manifest, err := utils.FindProjectManifest()
+	if err != nil {
+		return []DoctorFinding{{Check: utils.ManifestFile, Status: DoctorError, Message: err.Error()}}
+	}
+	if manifest == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range manifest.AliasNames() {
+		if utils.CheckVersionExists(manifest.Aliases[name]) != nil {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, manifest.Aliases[name]))
+		}
+	}
+	if len(missing) > 0 {
+		return []DoctorFinding{{
+			Check:   utils.ManifestFile,
+			Status:  DoctorWarning,
+			Message: fmt.Sprintf("project aliases point at versions that are not installed: %s", strings.Join(missing, ", ")),
+			Hint:    "run 'jfvm sync' to install everything the project declares",
+		}}
+	}
+	return []DoctorFinding{{
+		Check:   utils.ManifestFile,
+		Status:  DoctorOK,
+		Message: fmt.Sprintf("%s is valid and its aliases are installed", manifest.Path),
+	}}
+}
+
+func checkHistoryFile() []DoctorFinding {
+	finding := DoctorFinding{Check: "history", Status: DoctorOK}
+
+	entries, err := loadHistory(utils.JfvmHistory)
+	switch {
+	case os.IsNotExist(err):
+		finding.Message = "no history recorded yet"
+	case err != nil:
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+			finding.Status = DoctorError
+			finding.Message = fmt.Sprintf("cannot read %s: %v", utils.JfvmHistory, err)
+			return []DoctorFinding{finding}
+		}
+		backup := fmt.Sprintf("%s.corrupt-%s", utils.JfvmHistory, time.Now().Format("20060102150405"))
+		finding.Status = DoctorWarning
+		finding.Message = fmt.Sprintf("%s is corrupted: %v", utils.JfvmHistory, err)
+		finding.Hint = fmt.Sprintf("the fix moves it to %s and starts a fresh history", backup)
+		finding.Fix = func() error { return os.Rename(utils.JfvmHistory, backup) }
+	default:
+		finding.Message = fmt.Sprintf("%d entries", len(entries))
+	}
+	return []DoctorFinding{finding}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestCheckPermissionsCreatesMissingDirectories(t *testing.T) {
+	useTestRoot(t)
+
+	findings := checkPermissions()
+	if len(findings) != 4 {
+		t.Fatalf("got %d findings, want one per directory", len(findings))
+	}
+	captureStdout(t, func() {
+		if remaining := displayDoctorFindings(findings, true); remaining != 0 {
+			t.Errorf("%d problems remain after --fix", remaining)
+		}
+	})
+	for _, finding := range checkPermissions() {
+		if finding.Status != DoctorOK {
+			t.Errorf("after --fix: %s", finding.Message)
+		}
+	}
+}
+
+func TestCheckInstalledBinaries(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+	installTestVersion(t, "2.71.0")
+	if err := os.Chmod(filepath.Join(utils.JfvmVersions, "2.71.0", utils.BinaryName), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(utils.JfvmVersions, "my-build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := checkInstalledBinaries()
+	messages := map[string]bool{}
+	for _, finding := range findings {
+		messages[finding.Message] = finding.Fix != nil
+	}
+	for message, fixable := range map[string]bool{
+		"2.71.0 binary is not executable": true,
+		"my-build has no jf binary":       true,
+	} {
+		if got, ok := messages[message]; !ok || got != fixable {
+			t.Errorf("missing finding %q in %v", message, findings)
+		}
+	}
+
+	captureStdout(t, func() {
+		if remaining := displayDoctorFindings(findings, true); remaining != 0 {
+			t.Errorf("%d problems remain after --fix", remaining)
+		}
+	})
+	if info, err := os.Stat(filepath.Join(utils.JfvmVersions, "2.71.0", utils.BinaryName)); err != nil || info.Mode().Perm()&0111 == 0 {
+		t.Error("2.71.0 is still not executable")
+	}
+	// A linked version can't be downloaded again, so the fix moves it to the trash
+	if _, err := os.Stat(filepath.Join(utils.JfvmVersions, "my-build")); !os.IsNotExist(err) {
+		t.Error("my-build is still installed")
+	}
+}
+
+func TestCheckActiveVersion(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+	if findings := checkActiveVersion(); findings[0].Status != DoctorWarning {
+		t.Errorf("no version selected: got %v", findings[0])
+	}
+
+	for version, status := range map[string]DoctorStatus{"2.70.0": DoctorOK, "2.99.0": DoctorError} {
+		if err := os.WriteFile(utils.JfvmConfig, []byte(version+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		finding := checkActiveVersion()[0]
+		if finding.Status != status {
+			t.Errorf("%s: got status %d (%s), want %d", version, finding.Status, finding.Message, status)
+		}
+		if status == DoctorError && finding.Fix == nil {
+			t.Errorf("%s: a missing release can be reinstalled", version)
+		}
+	}
+}
+
+func TestCheckHistoryFileMovesCorruptHistoryAside(t *testing.T) {
+	useTestRoot(t)
+	if err := os.MkdirAll(utils.JfvmRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(utils.JfvmHistory, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	finding := checkHistoryFile()[0]
+	if finding.Status != DoctorWarning || finding.Fix == nil {
+		t.Fatalf("got %v, want a fixable warning", finding)
+	}
+	if err := finding.Fix(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(utils.JfvmHistory); !os.IsNotExist(err) {
+		t.Error("corrupt history is still in place")
+	}
+	matches, _ := filepath.Glob(utils.JfvmHistory + ".corrupt-*")
+	if len(matches) != 1 {
+		t.Errorf("got backups %v, want one", matches)
+	}
+	if finding := checkHistoryFile()[0]; finding.Status != DoctorOK {
+		t.Errorf("after the fix: %s", finding.Message)
+	}
+}
+
+func TestDisplayDoctorFindingsCountsUnfixedErrors(t *testing.T) {
+	findings := []DoctorFinding{
+		{Check: "a", Status: DoctorOK, Message: "fine"},
+		{Check: "b", Status: DoctorWarning, Message: "odd"},
+		{Check: "c", Status: DoctorError, Message: "broken", Fix: func() error { return nil }},
+		{Check: "d", Status: DoctorError, Message: "stuck", Fix: func() error { return errors.New("read-only") }},
+		{Check: "e", Status: DoctorError, Message: "manual", Hint: "do it by hand"},
+	}
+
+	var remaining int
+	stdout := captureStdout(t, func() { remaining = displayDoctorFindings(findings, false) })
+	if remaining != 3 {
+		t.Errorf("without --fix: %d errors remain, want 3", remaining)
+	}
+	if !strings.Contains(stdout, "can be repaired with 'jfvm doctor --fix'") {
+		t.Errorf("no --fix hint in:\n%s", stdout)
+	}
+
+	stdout = captureStdout(t, func() { remaining = displayDoctorFindings(findings, true) })
+	if remaining != 2 {
+		t.Errorf("with --fix: %d errors remain, want 2", remaining)
+	}
+	if !strings.Contains(stdout, "fix failed: read-only") || !strings.Contains(stdout, "1 fixed") {
+		t.Errorf("unexpected report:\n%s", stdout)
+	}
+}
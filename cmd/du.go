@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var Du = &cli.Command{
+	Name:        "du",
+	Usage:       descriptions.Du.Usage,
+	Description: descriptions.Du.Format(),
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dedup",
+			Usage: "Replace byte-identical binaries with hard links to a single copy",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "With --dedup, only show which binaries would be linked",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output",
+			Value: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("no-color") {
+			color.NoColor = true
+		}
+
+		if c.Bool("dedup") {
+			results, err := utils.DedupBinaries("", c.Bool("dry-run"))
+			if err != nil {
+				return err
+			}
+			displayDedup(results, c.Bool("dry-run"))
+			if c.Bool("dry-run") {
+				return nil
+			}
+		}
+
+		return displayDiskUsage()
+	},
+}
+
+func displayDedup(results []utils.DedupResult, dryRun bool) {
+	if len(results) == 0 {
+		fmt.Println("✅ No duplicate binaries found.")
+		fmt.Println()
+		return
+	}
+
+	var saved int64
+	for _, result := range results {
+		saved += result.Size
+		fmt.Printf("🔗 %s → %s (%s)\n", result.Version, result.LinkedTo, utils.FormatBytes(result.Size))
+	}
+	if dryRun {
+		fmt.Printf("📋 Deduplicating would save %s\n\n", utils.FormatBytes(saved))
+	} else {
+		fmt.Printf("✅ Deduplicated %d binaries, saved %s\n\n", len(results), utils.FormatBytes(saved))
+	}
+}
+
+func displayDiskUsage() error {
+	versions, err := utils.ListInstalledVersions()
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Println("📭 No versions installed.")
+		return nil
+	}
+
+	var (
+		blueColor   = color.New(color.FgBlue)
+		yellowColor = color.New(color.FgYellow, color.Bold)
+	)
+
+	fmt.Printf("💾 JFVM DISK USAGE\n")
+	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n\n")
+	fmt.Printf("%-20s %-12s %s\n", "VERSION", "SIZE", "SHARED WITH")
+	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+
+	// Files are counted once on disk no matter how many versions hard-link them
+	type seenFile struct {
+		version string
+		info    os.FileInfo
+	}
+	seen := make(map[int64][]seenFile)
+	var apparent, onDisk int64
+
+	for _, version := range versions {
+		var size int64
+		shared := map[string]bool{}
+		err := filepath.Walk(filepath.Join(utils.JfvmVersions, version), func(_ string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			size += info.Size()
+			for _, other := range seen[info.Size()] {
+				if os.SameFile(other.info, info) {
+					shared[other.version] = true
+					return nil
+				}
+			}
+			seen[info.Size()] = append(seen[info.Size()], seenFile{version, info})
+			onDisk += info.Size()
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to measure %s: %w", version, err)
+		}
+		apparent += size
+
+		sharedWith := ""
+		for other := range shared {
+			if sharedWith != "" {
+				sharedWith += ", "
+			}
+			sharedWith += other
+		}
+		fmt.Printf("%s %-12s %s\n", blueColor.Sprintf("%-20s", version), utils.FormatBytes(size), sharedWith)
+	}
+
+	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+	fmt.Printf("📦 Total: %s across %d versions, %s on disk", utils.FormatBytes(apparent), len(versions), yellowColor.Sprint(utils.FormatBytes(onDisk)))
+	if apparent > onDisk {
+		fmt.Printf(" (%s saved by hard links)", utils.FormatBytes(apparent-onDisk))
+	}
+	fmt.Printf("\n")
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+// Kinds of file changes.
+const (
+	FileAdded   = "added"
+	FileRemoved = "removed"
+	FileChanged = "changed"
+)
+
+// FileChange is one difference between the working directories two versions left behind.
+type FileChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	// Details describe changes other than text content, like modes or binary content
+	Details []string   `json:"details,omitempty"`
+	Hunks   []DiffHunk `json:"hunks,omitempty"`
+}
+
+// diffFileTrees compares two scanned trees. Both must be sorted by path, as ScanTree returns them.
+func diffFileTrees(old, new []utils.FileEntry) []FileChange {
+	var changes []FileChange
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case j >= len(new) || (i < len(old) && old[i].Path < new[j].Path):
+			changes = append(changes, FileChange{Path: old[i].Path, Kind: FileRemoved, Details: []string{describeFile(old[i])}})
+			i++
+		case i >= len(old) || new[j].Path < old[i].Path:
+			changes = append(changes, FileChange{Path: new[j].Path, Kind: FileAdded, Details: []string{describeFile(new[j])}})
+			j++
+		default:
+			if change, ok := diffFileEntries(old[i], new[j]); ok {
+				changes = append(changes, change)
+			}
+			i++
+			j++
+		}
+	}
+	return changes
+}
+
+// diffFileEntries compares two entries at the same path.
+func diffFileEntries(a, b utils.FileEntry) (FileChange, bool) {
+	change := FileChange{Path: a.Path, Kind: FileChanged}
+	if a.Mode.Type() != b.Mode.Type() {
+		change.Details = append(change.Details, fmt.Sprintf("%s → %s", fileType(a.Mode), fileType(b.Mode)))
+		return change, true
+	}
+	if a.Mode.Perm() != b.Mode.Perm() {
+		change.Details = append(change.Details, fmt.Sprintf("mode %s → %s", a.Mode.Perm(), b.Mode.Perm()))
+	}
+	if a.Link != b.Link {
+		change.Details = append(change.Details, fmt.Sprintf("link %s → %s", a.Link, b.Link))
+	}
+	if a.SHA256 != b.SHA256 {
+		if a.IsText && b.IsText {
+			change.Hunks = unifiedHunks(lineDiff(a.Text, b.Text), diffContextLines)
+		} else {
+			change.Details = append(change.Details, fmt.Sprintf("content differs (%s → %s)", utils.FormatBytes(a.Size), utils.FormatBytes(b.Size)))
+		}
+	}
+	return change, len(change.Details) > 0 || len(change.Hunks) > 0
+}
+
+func fileType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode&fs.ModeSymlink != 0:
+		return "symlink"
+	default:
+		return "file"
+	}
+}
+
+func describeFile(entry utils.FileEntry) string {
+	switch {
+	case entry.Mode.IsDir():
+		return fmt.Sprintf("directory, %s", entry.Mode.Perm())
+	case entry.Mode&fs.ModeSymlink != 0:
+		return "symlink to " + entry.Link
+	default:
+		return fmt.Sprintf("%s, %s", entry.Mode.Perm(), utils.FormatBytes(entry.Size))
+	}
+}
+
+func displayFileChanges(changes []FileChange, version1, version2 string) {
+	var (
+		redColor    = color.New(color.FgRed)
+		greenColor  = color.New(color.FgGreen)
+		yellowColor = color.New(color.FgYellow)
+		cyanColor   = color.New(color.FgCyan)
+	)
+
+	fmt.Printf("📁 FILE CHANGES (%d):\n", len(changes))
+	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+	fmt.Printf("%s %s\n", redColor.Sprint("---"), version1)
+	fmt.Printf("%s %s\n", greenColor.Sprint("+++"), version2)
+	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+
+	for _, change := range changes {
+		switch change.Kind {
+		case FileAdded:
+			fmt.Println(greenColor.Sprintf("+ %s (%s)", change.Path, change.Details[0]))
+		case FileRemoved:
+			fmt.Println(redColor.Sprintf("- %s (%s)", change.Path, change.Details[0]))
+		default:
+			fmt.Printf("%s %s\n", yellowColor.Sprint("~"), change.Path)
+			for _, detail := range change.Details {
+				fmt.Printf("    %s\n", detail)
+			}
+			for _, hunk := range change.Hunks {
+				fmt.Printf("    %s\n", cyanColor.Sprint(hunk.Header()))
+				for _, line := range hunk.Lines {
+					switch line[0] {
+					case lineDelete:
+						fmt.Printf("    %s\n", redColor.Sprint(line))
+					case lineInsert:
+						fmt.Printf("    %s\n", greenColor.Sprint(line))
+					default:
+						fmt.Printf("    %s\n", line)
+					}
+				}
+			}
+		}
+	}
+}
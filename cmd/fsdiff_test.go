@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+// writeTree creates files below root from a map of relative paths to contents.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func scanTestTree(t *testing.T, files map[string]string) []utils.FileEntry {
+	t.Helper()
+	root := t.TempDir()
+	writeTree(t, root, files)
+	entries, err := utils.ScanTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestDiffFileTreesSiblingDirectoryAndFile(t *testing.T) {
+	old := scanTestTree(t, map[string]string{"out/f": "one\n", "out.log": "log\n"})
+	new := scanTestTree(t, map[string]string{"out/f": "two\n", "out.log": "log\n"})
+
+	changes := diffFileTrees(old, new)
+	if len(changes) != 1 || changes[0].Path != "out/f" || changes[0].Kind != FileChanged {
+		t.Fatalf("got %+v, want only out/f changed", changes)
+	}
+
+	// Without out/, out.log must still pair up with itself
+	changes = diffFileTrees(old, scanTestTree(t, map[string]string{"out.log": "log\n"}))
+	if len(changes) != 2 || changes[0].Path != "out" || changes[1].Path != "out/f" || changes[0].Kind != FileRemoved || changes[1].Kind != FileRemoved {
+		t.Fatalf("got %+v, want out and out/f removed", changes)
+	}
+}
+
+func TestDiffFileTreesKinds(t *testing.T) {
+	old := scanTestTree(t, map[string]string{"keep.txt": "same\n", "gone.txt": "x\n", "edit.txt": "a\nb\n"})
+	new := scanTestTree(t, map[string]string{"keep.txt": "same\n", "edit.txt": "a\nc\n", "new/file.bin": "\x00\x01"})
+
+	kinds := map[string]string{}
+	for _, change := range diffFileTrees(old, new) {
+		kinds[change.Path] = change.Kind
+	}
+	want := map[string]string{"gone.txt": FileRemoved, "edit.txt": FileChanged, "new": FileAdded, "new/file.bin": FileAdded}
+	if len(kinds) != len(want) {
+		t.Fatalf("got %v, want %v", kinds, want)
+	}
+	for path, kind := range want {
+		if kinds[path] != kind {
+			t.Errorf("%s: got %q, want %q", path, kinds[path], kind)
+		}
+	}
+}
+
+func TestDiffFileEntriesShowsTextHunksAndModes(t *testing.T) {
+	a := utils.FileEntry{Path: "f", Mode: 0644, SHA256: "1", Text: "a\nb\n", IsText: true}
+	b := utils.FileEntry{Path: "f", Mode: 0755, SHA256: "2", Text: "a\nc\n", IsText: true}
+	change, ok := diffFileEntries(a, b)
+	if !ok || len(change.Hunks) == 0 || len(change.Details) != 1 {
+		t.Fatalf("got %+v", change)
+	}
+	if _, ok := diffFileEntries(a, a); ok {
+		t.Error("identical entries reported as changed")
+	}
+}
+
+func TestNormalizeResultUpdatesFileChecksums(t *testing.T) {
+	normalizer, err := utils.NormalizationRules{Builtins: []string{"timestamps"}}.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []ExecutionResult{
+		{Files: scanTestTree(t, map[string]string{"build-info.json": `{"started": "2025-03-01T10:00:00Z"}`})},
+		{Files: scanTestTree(t, map[string]string{"build-info.json": `{"started": "2025-04-02T11:30:00Z"}`})},
+	}
+	if len(diffFileTrees(results[0].Files, results[1].Files)) == 0 {
+		t.Fatal("the files don't differ before normalizing")
+	}
+	for i := range results {
+		normalizeResult(&results[i], normalizer)
+	}
+	if changes := diffFileTrees(results[0].Files, results[1].Files); len(changes) > 0 {
+		t.Errorf("normalized files still differ: %+v", changes)
+	}
+}
+
+func TestCompareFsDiffWithSeed(t *testing.T) {
+	useTestRoot(t)
+	seed := t.TempDir()
+	writeTree(t, seed, map[string]string{"input.txt": "data\n"})
+	installTestScript(t, "2.70.0", `cat input.txt > out.txt`)
+	installTestScript(t, "2.71.0", `cat input.txt > out.txt; echo debug > extra.log; rm input.txt`)
+
+	var err error
+	stdout := captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --seed "+seed+" --format json 2.70.0 2.71.0 -- rt download")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report CompareReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout)
+	}
+	if len(report.Diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1", len(report.Diffs))
+	}
+	kinds := map[string]string{}
+	for _, change := range report.Diffs[0].FileChanges {
+		kinds[change.Path] = change.Kind
+	}
+	if want := map[string]string{"extra.log": FileAdded, "input.txt": FileRemoved}; len(kinds) != len(want) || kinds["extra.log"] != FileAdded || kinds["input.txt"] != FileRemoved {
+		t.Errorf("file changes are %v, want %v", kinds, want)
+	}
+	if data, _ := os.ReadFile(filepath.Join(seed, "input.txt")); string(data) != "data\n" {
+		t.Error("a version changed the seed directory")
+	}
+
+	file := filepath.Join(seed, "input.txt")
+	if err := runCommandLine(t, "jfvm compare --seed "+file+" 2.70.0 2.71.0 -- rt download"); err == nil || err.Error() != "seed "+file+" is not a directory" {
+		t.Errorf("a seed that is a file: got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// fakeCLIEnv makes the test binary act as a JFrog CLI of the given version, so tests have a
+// real executable for the host platform to link.
+const fakeCLIEnv = "JFVM_TEST_FAKE_CLI"
+
+func TestMain(m *testing.M) {
+	if version := os.Getenv(fakeCLIEnv); version != "" {
+		fmt.Printf("jf version %s\n", version)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeCLIBinary copies the test binary to a temporary jf that reports version.
+func fakeCLIBinary(t *testing.T, version string) string {
+	t.Helper()
+	t.Setenv(fakeCLIEnv, version)
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), utils.BinaryName)
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useTestRoot points every jfvm path at a fresh directory for the duration of the test.
+func useTestRoot(t *testing.T) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "."+utils.ToolName)
+	paths := map[*string]string{
+		&utils.JfvmRoot:            root,
+		&utils.JfvmConfig:          filepath.Join(root, utils.ConfigFile),
+		&utils.JfvmVersions:        filepath.Join(root, utils.VersionsDir),
+		&utils.JfvmAliases:         filepath.Join(root, utils.AliasesDir),
+		&utils.JfvmAliasMeta:       filepath.Join(root, utils.AliasMetaDir),
+		&utils.JfvmShim:            filepath.Join(root, utils.ShimDir),
+		&utils.JfvmHistory:         filepath.Join(root, utils.HistoryFile),
+		&utils.JfvmJournal:         filepath.Join(root, utils.JournalFile),
+		&utils.JfvmProjects:        filepath.Join(root, utils.ProjectsFile),
+		&utils.JfvmTrash:           filepath.Join(root, utils.TrashDir),
+		&utils.JfvmSources:         filepath.Join(root, utils.SourcesDir),
+		&utils.JfvmCompareProfiles: filepath.Join(root, utils.ProfilesFile),
+		&utils.JfvmSnapshots:       filepath.Join(root, utils.SnapshotsDir),
+		&utils.JfvmRecordings:      filepath.Join(root, utils.RecordingsDir),
+	}
+	for path, value := range paths {
+		previous := *path
+		*path = value
+		t.Cleanup(func() { *path = previous })
+	}
+	return root
+}
+
+// installTestVersion installs a jf script that reports version and echoes its arguments.
+func installTestVersion(t *testing.T, version string) {
+	t.Helper()
+	installTestScript(t, version, "if [ \"$1\" = --version ]; then echo \"jf version "+version+"\"; else echo \"$@\"; fi")
+}
+
+// installTestScript installs version as a shell script running body.
+func installTestScript(t *testing.T, version, body string) {
+	t.Helper()
+	dir := filepath.Join(utils.JfvmVersions, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, utils.BinaryName), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// documentedExample returns the example of a command description that contains marker.
+func documentedExample(t *testing.T, description descriptions.CommandDescription, marker string) string {
+	t.Helper()
+	for _, example := range description.Examples {
+		if strings.Contains(example.Command, marker) {
+			return example.Command
+		}
+	}
+	t.Fatalf("no documented example contains %q", marker)
+	return ""
+}
+
+// runCommandLine runs a jfvm command line as a shell would split it, without exiting the
+// test process on errors.
+func runCommandLine(t *testing.T, line string) error {
+	t.Helper()
+	app := NewApp()
+	app.ExitErrHandler = func(*cli.Context, error) {}
+	return app.Run(shellFields(line))
+}
+
+// shellFields splits a command line at spaces, keeping single- and double-quoted parts together.
+func shellFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	inField := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			field.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inField = r, true
+		case r == ' ':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// captureStdout runs fn and returns what it printed to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	os.Stdout = stdout
+	_ = writer.Close()
+	return <-output
+}
@@ -3,15 +3,39 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/internal"
 	"github.com/urfave/cli/v2"
 )
 
 var Install = &cli.Command{
-	Name:      "install",
-	Usage:     "Install a specific version of JFrog CLI",
-	ArgsUsage: "[version]",
+	Name:        "install",
+	Usage:       "Install a specific version of JFrog CLI",
+	ArgsUsage:   "[version]",
+	Description: descriptions.Install.Format(),
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from-source",
+			Usage: "Build jf from <git-url-or-path>@<ref> instead of downloading a release",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Version name for --from-source builds (default: src-<ref>)",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Rebuild --from-source even if the ref still points at the installed commit",
+			Value: false,
+		},
+	},
 	Action: func(c *cli.Context) error {
+		if spec := c.String("from-source"); spec != "" {
+			if c.Args().Len() > 0 {
+				return cli.Exit("--from-source cannot be combined with a version argument; use --name to choose the version name", 1)
+			}
+			return installFromSource(spec, c.String("name"), c.Bool("force"))
+		}
+
 		if c.Args().Len() != 1 {
 			return cli.Exit("Please provide a version (e.g., 2.57.0)", 1)
 		}
@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of JSON changes.
+const (
+	JSONAdded   = "added"
+	JSONRemoved = "removed"
+	JSONChanged = "changed"
+)
+
+// JSONChange is one difference between two JSON documents, located by its JSON path.
+// Old and New are always written, since null is a value: Kind tells which side is missing.
+type JSONChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// JSONDiffOptions controls how JSON outputs are compared.
+type JSONDiffOptions struct {
+	// Disabled forces a text diff even when both outputs are JSON
+	Disabled         bool
+	IgnoreArrayOrder bool
+	// IgnorePaths are path patterns like $.meta.took, $.files[*].created or $..timestamp
+	IgnorePaths []string
+}
+
+var plainKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// parseJSONOutput parses output that is a single JSON object or array. Scalars are not
+// treated as JSON, since plain outputs like "42" or "true" are better diffed as text.
+func parseJSONOutput(output string) (any, bool) {
+	trimmed := strings.TrimSpace(output)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	// Trailing content means this was not one JSON document
+	if decoder.More() {
+		return nil, false
+	}
+	return value, true
+}
+
+// diffJSONOutputs diffs two outputs structurally. ok is false when JSON diffing is disabled
+// or either output isn't JSON, in which case the outputs should be diffed as text.
+func diffJSONOutputs(output1, output2 string, opts JSONDiffOptions) ([]JSONChange, bool) {
+	if opts.Disabled {
+		return nil, false
+	}
+	value1, ok1 := parseJSONOutput(output1)
+	value2, ok2 := parseJSONOutput(output2)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+
+	var ignore [][]string
+	for _, pattern := range opts.IgnorePaths {
+		ignore = append(ignore, splitJSONPath(pattern))
+	}
+	d := jsonDiffer{opts: opts, ignore: ignore}
+	d.diff("$", nil, value1, value2)
+	return d.changes, true
+}
+
+type jsonDiffer struct {
+	opts    JSONDiffOptions
+	ignore  [][]string
+	changes []JSONChange
+}
+
+func (d *jsonDiffer) ignored(segments []string) bool {
+	for _, pattern := range d.ignore {
+		if matchJSONPath(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *jsonDiffer) add(path string, segments []string, change JSONChange) {
+	if d.ignored(segments) {
+		return
+	}
+	change.Path = path
+	d.changes = append(d.changes, change)
+}
+
+func (d *jsonDiffer) diff(path string, segments []string, a, b any) {
+	if d.ignored(segments) {
+		return
+	}
+
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			d.add(path, segments, JSONChange{Kind: JSONChanged, Old: a, New: b})
+			return
+		}
+		keys := make(map[string]bool)
+		for key := range av {
+			keys[key] = true
+		}
+		for key := range bv {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			childPath, childSegments := jsonKeyPath(path, key), append(segments[:len(segments):len(segments)], key)
+			aChild, inA := av[key]
+			bChild, inB := bv[key]
+			switch {
+			case !inB:
+				d.add(childPath, childSegments, JSONChange{Kind: JSONRemoved, Old: aChild})
+			case !inA:
+				d.add(childPath, childSegments, JSONChange{Kind: JSONAdded, New: bChild})
+			default:
+				d.diff(childPath, childSegments, aChild, bChild)
+			}
+		}
+
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			d.add(path, segments, JSONChange{Kind: JSONChanged, Old: a, New: b})
+			return
+		}
+		if d.opts.IgnoreArrayOrder {
+			d.diffUnordered(path, segments, av, bv)
+			return
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			childPath, childSegments := fmt.Sprintf("%s[%d]", path, i), append(segments[:len(segments):len(segments)], fmt.Sprintf("[%d]", i))
+			switch {
+			case i >= len(bv):
+				d.add(childPath, childSegments, JSONChange{Kind: JSONRemoved, Old: av[i]})
+			case i >= len(av):
+				d.add(childPath, childSegments, JSONChange{Kind: JSONAdded, New: bv[i]})
+			default:
+				d.diff(childPath, childSegments, av[i], bv[i])
+			}
+		}
+
+	default:
+		if !equalJSONScalars(a, b) {
+			d.add(path, segments, JSONChange{Kind: JSONChanged, Old: a, New: b})
+		}
+	}
+}
+
+// equalJSONScalars compares numbers by value, so 1 equals 1.0 and 1e2 equals 100, and
+// other scalars by their JSON encoding.
+func equalJSONScalars(a, b any) bool {
+	an, aIsNumber := a.(json.Number)
+	bn, bIsNumber := b.(json.Number)
+	if aIsNumber && bIsNumber {
+		return canonicalNumber(an) == canonicalNumber(bn)
+	}
+	return canonicalJSON(a) == canonicalJSON(b)
+}
+
+// canonicalNumber rewrites a JSON number as <digits>e<exponent> without leading or trailing
+// zeros, which is the same text for every spelling of a value. It works on the decimal text,
+// so large integers and long fractions are compared exactly.
+func canonicalNumber(n json.Number) json.Number {
+	s, sign := string(n), ""
+	if strings.HasPrefix(s, "-") {
+		s, sign = s[1:], "-"
+	}
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		parsed, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return n
+		}
+		s, exponent = s[:i], parsed
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+	digits := strings.TrimLeft(whole+fraction, "0")
+	exponent -= len(fraction)
+	if digits == "" {
+		return "0"
+	}
+	significant := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(significant)
+	return json.Number(fmt.Sprintf("%s%se%d", sign, significant, exponent))
+}
+
+// diffUnordered compares arrays as multisets: elements are paired with an equal element
+// anywhere in the other array, and only unpaired elements are reported.
+func (d *jsonDiffer) diffUnordered(path string, segments []string, a, b []any) {
+	// Ignored paths inside elements must not make otherwise equal elements differ
+	key := func(value any, index int) string {
+		sub := jsonDiffer{opts: d.opts, ignore: d.ignore}
+		elementSegments := append(segments[:len(segments):len(segments)], fmt.Sprintf("[%d]", index))
+		return canonicalJSON(sub.strip(elementSegments, value))
+	}
+
+	remaining := make(map[string][]int)
+	for j, value := range b {
+		k := key(value, j)
+		remaining[k] = append(remaining[k], j)
+	}
+	matched := make(map[int]bool)
+	for i, value := range a {
+		k := key(value, i)
+		if indexes := remaining[k]; len(indexes) > 0 {
+			matched[indexes[0]] = true
+			remaining[k] = indexes[1:]
+			continue
+		}
+		d.add(fmt.Sprintf("%s[%d]", path, i), append(segments[:len(segments):len(segments)], fmt.Sprintf("[%d]", i)), JSONChange{Kind: JSONRemoved, Old: value})
+	}
+	for j, value := range b {
+		if !matched[j] {
+			d.add(fmt.Sprintf("%s[%d]", path, j), append(segments[:len(segments):len(segments)], fmt.Sprintf("[%d]", j)), JSONChange{Kind: JSONAdded, New: value})
+		}
+	}
+}
+
+// strip returns value without the members that match ignored paths.
+func (d *jsonDiffer) strip(segments []string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		stripped := make(map[string]any)
+		for key, child := range v {
+			childSegments := append(segments[:len(segments):len(segments)], key)
+			if !d.ignored(childSegments) {
+				stripped[key] = d.strip(childSegments, child)
+			}
+		}
+		return stripped
+	case []any:
+		stripped := make([]any, 0, len(v))
+		for i, child := range v {
+			childSegments := append(segments[:len(segments):len(segments)], fmt.Sprintf("[%d]", i))
+			if !d.ignored(childSegments) {
+				stripped = append(stripped, d.strip(childSegments, child))
+			}
+		}
+		if d.opts.IgnoreArrayOrder {
+			sort.Slice(stripped, func(i, j int) bool { return canonicalJSON(stripped[i]) < canonicalJSON(stripped[j]) })
+		}
+		return stripped
+	case json.Number:
+		return canonicalNumber(v)
+	default:
+		return value
+	}
+}
+
+func jsonKeyPath(path, key string) string {
+	if plainKeyPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// splitJSONPath splits a path pattern like $.files[*].name or files[0]["a b"] into segments.
+// Keys are plain segments, indexes are kept in brackets, and ".." becomes "**".
+func splitJSONPath(pattern string) []string {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "$")
+	var segments []string
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], ".."):
+			segments = append(segments, "**")
+			i++
+		case pattern[i] == '.':
+			i++
+		case pattern[i] == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				end = len(pattern) - i - 1
+			}
+			inner := pattern[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				segments = append(segments, unquoted)
+			} else {
+				segments = append(segments, "["+inner+"]")
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(pattern[i:], ".[")
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			segments = append(segments, pattern[i:i+end])
+			i += end
+		}
+	}
+	return segments
+}
+
+// matchJSONPath matches path segments against a pattern in which "*" matches any key,
+// "[*]" any index and "**" any number of segments.
+func matchJSONPath(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchJSONPath(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	head, segment := pattern[0], segments[0]
+	isIndex := strings.HasPrefix(segment, "[")
+	switch {
+	case head == "*" && !isIndex, head == "[*]" && isIndex, head == segment:
+		return matchJSONPath(pattern[1:], segments[1:])
+	default:
+		return false
+	}
+}
+
+// canonicalJSON renders a value with sorted keys, for comparisons and display.
+func canonicalJSON(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// formatJSONValue renders a value for one line of a JSON diff.
+func formatJSONValue(value any) string {
+	s := canonicalJSON(value)
+	if runes := []rune(s); len(runes) > 80 {
+		return string(runes[:77]) + "..."
+	}
+	return s
+}
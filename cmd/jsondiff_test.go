@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDiffJSONOutputsComparesNumbersByValue(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		opts    JSONDiffOptions
+		changes int
+	}{
+		{`{"n": 1}`, `{"n": 1.0}`, JSONDiffOptions{}, 0},
+		{`{"n": 1e2}`, `{"n": 100}`, JSONDiffOptions{}, 0},
+		{`{"n": -0.50}`, `{"n": -5E-1}`, JSONDiffOptions{}, 0},
+		{`{"n": 0}`, `{"n": -0.0}`, JSONDiffOptions{}, 0},
+		{`{"n": 9007199254740993}`, `{"n": 9007199254740992}`, JSONDiffOptions{}, 1},
+		{`{"n": 10}`, `{"n": 1}`, JSONDiffOptions{}, 1},
+		{`{"n": 1}`, `{"n": "1"}`, JSONDiffOptions{}, 1},
+		{`[1, 2.50]`, `[2.5, 1.0]`, JSONDiffOptions{IgnoreArrayOrder: true}, 0},
+	}
+	for _, test := range tests {
+		changes, ok := diffJSONOutputs(test.a, test.b, test.opts)
+		if !ok {
+			t.Fatalf("%s vs %s: not diffed as JSON", test.a, test.b)
+		}
+		if len(changes) != test.changes {
+			t.Errorf("%s vs %s: got %d changes %v, want %d", test.a, test.b, len(changes), changes, test.changes)
+		}
+	}
+}
+
+func TestJSONChangeKeepsNull(t *testing.T) {
+	changes, ok := diffJSONOutputs(`{"owner": null}`, `{"owner": "admin"}`, JSONDiffOptions{})
+	if !ok || len(changes) != 1 {
+		t.Fatalf("got %v, want one change", changes)
+	}
+	data, err := json.Marshal(changes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"path":"$.owner","kind":"changed","old":null,"new":"admin"}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+// changeSummaries renders changes as "<kind> <path>" for compact comparisons.
+func changeSummaries(changes []JSONChange) []string {
+	var summaries []string
+	for _, change := range changes {
+		summaries = append(summaries, fmt.Sprintf("%s %s", change.Kind, change.Path))
+	}
+	return summaries
+}
+
+func TestDiffJSONOutputsPaths(t *testing.T) {
+	before := `{"name": "repo", "size": 1, "tags": ["a", "b"], "files": [{"name": "x"}], "odd key": 1}`
+	after := `{"name": "repo", "size": 2, "tags": ["a"], "files": [{"name": "x"}, {"name": "y"}], "odd key": 2, "new": true}`
+	changes, ok := diffJSONOutputs(before, after, JSONDiffOptions{})
+	if !ok {
+		t.Fatal("not diffed as JSON")
+	}
+	want := []string{
+		"added $.files[1]",
+		"added $.new",
+		`changed $["odd key"]`,
+		"changed $.size",
+		"removed $.tags[1]",
+	}
+	if got := changeSummaries(changes); !slices.Equal(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestDiffJSONOutputsIgnorePaths(t *testing.T) {
+	before := `{"meta": {"took": 5, "timestamp": "t1"}, "files": [{"name": "a", "created": "t1"}], "timestamp": "t1", "size": 1}`
+	after := `{"meta": {"took": 7, "timestamp": "t2"}, "files": [{"name": "a", "created": "t2"}], "timestamp": "t2", "size": 2}`
+	tests := []struct {
+		ignore []string
+		want   []string
+	}{
+		{nil, []string{"changed $.files[0].created", "changed $.meta.timestamp", "changed $.meta.took", "changed $.size", "changed $.timestamp"}},
+		{[]string{"$.meta.took", "$.files[*].created"}, []string{"changed $.meta.timestamp", "changed $.size", "changed $.timestamp"}},
+		{[]string{"$..timestamp"}, []string{"changed $.files[0].created", "changed $.meta.took", "changed $.size"}},
+		{[]string{"$.meta", "$.*[0].created", "timestamp"}, []string{"changed $.size"}},
+	}
+	for _, test := range tests {
+		changes, _ := diffJSONOutputs(before, after, JSONDiffOptions{IgnorePaths: test.ignore})
+		if got := changeSummaries(changes); !slices.Equal(got, test.want) {
+			t.Errorf("%q: got %q\nwant %q", test.ignore, got, test.want)
+		}
+	}
+}
+
+func TestDiffJSONOutputsIgnoreArrayOrder(t *testing.T) {
+	before := `{"repos": [{"key": "a", "took": 1}, {"key": "b", "took": 2}, {"key": "b", "took": 3}]}`
+	after := `{"repos": [{"key": "b", "took": 9}, {"key": "c", "took": 9}, {"key": "a", "took": 9}]}`
+	opts := JSONDiffOptions{IgnoreArrayOrder: true, IgnorePaths: []string{"$.repos[*].took"}}
+	changes, _ := diffJSONOutputs(before, after, opts)
+	// One of the two b entries has no partner, and c is new
+	if got, want := changeSummaries(changes), []string{"removed $.repos[2]", "added $.repos[1]"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Nested arrays are unordered too
+	changes, _ = diffJSONOutputs(`[[1, 2], [3]]`, `[[3], [2, 1]]`, JSONDiffOptions{IgnoreArrayOrder: true})
+	if len(changes) != 0 {
+		t.Errorf("nested arrays differ: %v", changes)
+	}
+}
+
+func TestParseJSONOutput(t *testing.T) {
+	for output, isJSON := range map[string]bool{
+		` {"a": 1}` + "\n":  true,
+		`[1, 2]`:            true,
+		`42`:                false,
+		`true`:              false,
+		`{"a": 1} {"b": 2}`: false,
+		`{"a": `:            false,
+		``:                  false,
+	} {
+		if _, ok := parseJSONOutput(output); ok != isJSON {
+			t.Errorf("%q: parsed as JSON is %v, want %v", output, ok, isJSON)
+		}
+	}
+	if _, ok := diffJSONOutputs(`{}`, `{}`, JSONDiffOptions{Disabled: true}); ok {
+		t.Error("diffed as JSON with JSON diffing disabled")
+	}
+}
+
+func TestSplitJSONPath(t *testing.T) {
+	for pattern, want := range map[string][]string{
+		"$.meta.took":        {"meta", "took"},
+		"$.files[*].created": {"files", "[*]", "created"},
+		"$..timestamp":       {"**", "timestamp"},
+		`files[0]["a b"].x`:  {"files", "[0]", "a b", "x"},
+		"$":                  nil,
+	} {
+		if got := splitJSONPath(pattern); !slices.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Kinds of diffLine.
+const (
+	lineEqual  = ' '
+	lineDelete = '-'
+	lineInsert = '+'
+)
+
+// diffLine is one line of a line-level diff.
+type diffLine struct {
+	Kind byte
+	Text string
+}
+
+// DiffHunk is a unified diff hunk: a run of changes with surrounding context.
+type DiffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// lineDiff aligns two texts line by line.
+func lineDiff(text1, text2 string) []diffLine {
+	// Every distinct line becomes one rune, so a character diff of the runes is a line diff.
+	// DiffLinesToChars isn't used: its comma-separated encoding breaks with many lines.
+	var lines []string
+	index := make(map[string]rune)
+	encode := func(text string) []rune {
+		var runes []rune
+		if text == "" {
+			return runes
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			r, ok := index[line]
+			if !ok {
+				r = lineRune(len(lines))
+				index[line] = r
+				lines = append(lines, line)
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+	runes1, runes2 := encode(text1), encode(text2)
+
+	dmp := diffmatchpatch.New()
+	var result []diffLine
+	for _, d := range dmp.DiffMainRunes(runes1, runes2, false) {
+		kind := byte(lineEqual)
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			kind = lineDelete
+		case diffmatchpatch.DiffInsert:
+			kind = lineInsert
+		}
+		for _, r := range d.Text {
+			result = append(result, diffLine{Kind: kind, Text: lines[runeLine(r)]})
+		}
+	}
+	return result
+}
+
+// lineRune maps a line index to a rune that survives conversion to a string, skipping the
+// surrogate range.
+func lineRune(i int) rune {
+	r := rune(i + 1)
+	if r >= 0xD800 {
+		r += 0x800
+	}
+	return r
+}
+
+func runeLine(r rune) int {
+	if r >= 0xD800+0x800 {
+		r -= 0x800
+	}
+	return int(r) - 1
+}
+
+// unifiedHunks groups a line diff into hunks with the given number of context lines.
+func unifiedHunks(lines []diffLine, context int) []DiffHunk {
+	var hunks []DiffHunk
+	oldLine, newLine := 1, 1
+	var current *DiffHunk
+	lastChange := -1
+
+	for i, line := range lines {
+		if line.Kind != lineEqual {
+			if current == nil || i-lastChange > 2*context {
+				if current != nil {
+					hunks = append(hunks, trimHunk(*current, context))
+				}
+				// Leading context lines are unchanged, so they count on both sides
+				start := max(i-context, 0)
+				current = &DiffHunk{OldStart: oldLine - (i - start), NewStart: newLine - (i - start)}
+				for _, ctx := range lines[start:i] {
+					current.Lines = append(current.Lines, " "+ctx.Text)
+					current.OldLines++
+					current.NewLines++
+				}
+			}
+			lastChange = i
+		}
+
+		if current != nil {
+			current.Lines = append(current.Lines, string(line.Kind)+line.Text)
+			if line.Kind != lineInsert {
+				current.OldLines++
+			}
+			if line.Kind != lineDelete {
+				current.NewLines++
+			}
+		}
+
+		if line.Kind != lineInsert {
+			oldLine++
+		}
+		if line.Kind != lineDelete {
+			newLine++
+		}
+	}
+	if current != nil {
+		hunks = append(hunks, trimHunk(*current, context))
+	}
+	return hunks
+}
+
+// trimHunk drops the context that was collected after the last change beyond the limit.
+func trimHunk(hunk DiffHunk, context int) DiffHunk {
+	trailing := 0
+	for i := len(hunk.Lines) - 1; i >= 0 && hunk.Lines[i][0] == lineEqual; i-- {
+		trailing++
+	}
+	if extra := trailing - context; extra > 0 {
+		hunk.Lines = hunk.Lines[:len(hunk.Lines)-extra]
+		hunk.OldLines -= extra
+		hunk.NewLines -= extra
+	}
+	return hunk
+}
@@ -1,61 +1,311 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
 	"github.com/urfave/cli/v2"
 )
 
+const watchInterval = time.Second
+
 var Link = &cli.Command{
-	Name:  "link",
-	Usage: "Link a local jf binary into jfvm",
+	Name:        "link",
+	Usage:       "Link a local jf binary into jfvm",
+	Description: descriptions.Link.Format(),
 	Flags: []cli.Flag{
-		&cli.StringFlag{Name: "from", Usage: "Path to the local jf binary", Required: true},
+		&cli.StringFlag{Name: "from", Usage: "Path to the local jf binary"},
 		&cli.StringFlag{Name: "name", Usage: "Version name to assign", Required: true},
+		&cli.BoolFlag{Name: "symlink", Usage: "Reference the binary in place instead of copying it"},
+		&cli.BoolFlag{Name: "watch", Usage: "Keep running and re-copy the binary whenever it changes"},
+		&cli.StringFlag{Name: "build", Usage: "Build jf with 'go build' from a local jfrog-cli checkout"},
 	},
 	Action: func(c *cli.Context) error {
 		from := c.String("from")
 		name := c.String("name")
+		repo := c.String("build")
 
-		if _, err := os.Stat(from); os.IsNotExist(err) {
-			return fmt.Errorf("no such file: %s", from)
+		switch {
+		case from == "" && repo == "":
+			return cli.Exit("Please provide --from <binary> or --build <repo-path>", 1)
+		case from != "" && repo != "":
+			return cli.Exit("--from and --build cannot be used together", 1)
+		case c.Bool("symlink") && c.Bool("watch"):
+			return cli.Exit("--watch is not needed with --symlink: the link always points at the current binary", 1)
+		case repo != "" && (c.Bool("symlink") || c.Bool("watch")):
+			return cli.Exit("--symlink and --watch only apply to --from", 1)
 		}
 
-		targetDir := filepath.Join(utils.JfvmVersions, name)
-		targetBin := filepath.Join(targetDir, utils.BinaryName)
-		err := os.MkdirAll(targetDir, 0755)
-		if err != nil {
-			return err
+		if repo != "" {
+			return buildAndLink(repo, name)
 		}
 
-		src, err := os.Open(from)
+		absFrom, err := filepath.Abs(from)
 		if err != nil {
 			return err
 		}
-		defer func(src *os.File) {
-			_ = src.Close()
-		}(src)
-
-		dst, err := os.Create(targetBin)
+		if _, err := os.Stat(absFrom); os.IsNotExist(err) {
+			return fmt.Errorf("no such file: %s", from)
+		}
+		cliVersion, err := validateLinkSource(absFrom, name)
 		if err != nil {
-			return err
+			return cli.Exit(err.Error(), 1)
 		}
-		defer func(dst *os.File) {
-			_ = dst.Close()
-		}(dst)
 
-		if _, err := io.Copy(dst, src); err != nil {
-			return err
+		if c.Bool("symlink") {
+			if err := symlinkBinary(absFrom, name, cliVersion); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Linked %s (jf %s) as jfvm version %s (symlink, rebuilds are picked up automatically)\n", absFrom, cliVersion, name)
+			return nil
 		}
-		if err := os.Chmod(targetBin, 0755); err != nil {
+
+		if err := copyLinkedBinary(absFrom, name, cliVersion); err != nil {
 			return err
 		}
+		fmt.Printf("✅ Linked %s (jf %s) as jfvm version %s\n", absFrom, cliVersion, name)
 
-		fmt.Printf("✅ Linked %s as jfvm version %s\n", from, name)
+		if c.Bool("watch") {
+			return watchLinkedBinary(absFrom, name)
+		}
 		return nil
 	},
 }
+
+// validateLinkSource makes sure from is a JFrog CLI binary that can run on this machine
+// and returns the version it reports.
+func validateLinkSource(from, name string) (string, error) {
+	if utils.IsShimPath(from) {
+		return "", fmt.Errorf("%s is the jfvm shim; link the real jf binary instead", from)
+	}
+	if info, err := os.Stat(from); err == nil {
+		if target, err := os.Stat(filepath.Join(utils.JfvmVersions, name, utils.BinaryName)); err == nil && os.SameFile(info, target) {
+			return "", fmt.Errorf("%s already is the binary of version %s", from, name)
+		}
+	}
+	if err := utils.ValidateExecutable(from); err != nil {
+		return "", err
+	}
+	return utils.ReportedCLIVersion(from)
+}
+
+// copyLinkedBinary copies from into the version directory of name and records where it came from.
+func copyLinkedBinary(from, name, cliVersion string) error {
+	targetDir := filepath.Join(utils.JfvmVersions, name)
+	targetBin := filepath.Join(targetDir, utils.BinaryName)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return err
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func(src *os.File) {
+		_ = src.Close()
+	}(src)
+
+	dst, err := utils.CreateFileReplacing(targetBin)
+	if err != nil {
+		return err
+	}
+	defer func(dst *os.File) {
+		_ = dst.Close()
+	}(dst)
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+	if err := os.Chmod(targetBin, 0755); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	if linked, err := utils.DedupBinaries(name, false); err == nil && len(linked) > 0 {
+		fmt.Printf("🔗 %s is identical to %s, stored once\n", name, linked[0].LinkedTo)
+	}
+
+	return utils.SaveVersionMetadata(name, utils.VersionMetadata{
+		Source:     utils.SourceLink,
+		From:       from,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func symlinkBinary(from, name, cliVersion string) error {
+	targetDir := filepath.Join(utils.JfvmVersions, name)
+	targetBin := filepath.Join(targetDir, utils.BinaryName)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(targetBin); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(from, targetBin); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+
+	return utils.SaveVersionMetadata(name, utils.VersionMetadata{
+		Source:     utils.SourceLink,
+		From:       from,
+		Symlink:    true,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// watchLinkedBinary polls from and re-copies it whenever it changes, until interrupted.
+// A change is only picked up once the file stopped changing for one interval, so a
+// binary that is still being written by the compiler is never copied half-way.
+func watchLinkedBinary(from, name string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("👀 Watching %s for changes (Ctrl+C to stop)\n", from)
+
+	last, _ := os.Stat(from)
+	var pending os.FileInfo
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n👋 Stopped watching.")
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(from)
+		if err != nil {
+			// Build tools often delete the output before writing a new one
+			pending = nil
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		if pending == nil || !info.ModTime().Equal(pending.ModTime()) || info.Size() != pending.Size() {
+			pending = info
+			continue
+		}
+
+		last, pending = info, nil
+		cliVersion, err := validateLinkSource(from, name)
+		if err == nil {
+			err = copyLinkedBinary(from, name, cliVersion)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Not re-linking %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("🔄 %s Re-linked %s (jf %s, %s)\n", time.Now().Format("15:04:05"), name, cliVersion, utils.FormatBytes(info.Size()))
+	}
+}
+
+// buildAndLink builds jf from a local jfrog-cli checkout into the version directory of name.
+func buildAndLink(repo, name string) error {
+	absRepo, err := filepath.Abs(repo)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(absRepo, "go.mod")); err != nil {
+		return fmt.Errorf("%s is not a Go module (no go.mod found)", absRepo)
+	}
+
+	commit, dirty := gitState(absRepo)
+	describe := commit
+	if len(describe) > 12 {
+		describe = describe[:12]
+	}
+	if dirty {
+		describe += " (dirty)"
+	}
+	if describe != "" {
+		fmt.Printf("🔨 Building %s at %s...\n", absRepo, describe)
+	} else {
+		fmt.Printf("🔨 Building %s...\n", absRepo)
+	}
+
+	cliVersion, err := goBuildJf(absRepo, name)
+	if err != nil {
+		return err
+	}
+	if linked, err := utils.DedupBinaries(name, false); err == nil && len(linked) > 0 {
+		fmt.Printf("🔗 %s is identical to %s, stored once\n", name, linked[0].LinkedTo)
+	}
+
+	if err := utils.SaveVersionMetadata(name, utils.VersionMetadata{
+		Source:     utils.SourceBuild,
+		Repo:       absRepo,
+		GitCommit:  commit,
+		GitDirty:   dirty,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Built and linked %s (jf %s) as jfvm version %s\n", absRepo, cliVersion, name)
+	return nil
+}
+
+// goBuildJf runs 'go build' in dir, installs the result as the binary of version name and
+// returns the version it reports. The build writes to a temporary file first so a failed or
+// invalid build leaves an existing version intact.
+func goBuildJf(dir, name string) (string, error) {
+	targetDir := filepath.Join(utils.JfvmVersions, name)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", err
+	}
+	tmp := filepath.Join(targetDir, ".jf-build")
+	defer os.Remove(tmp)
+
+	cmd := exec.Command("go", "build", "-o", tmp, ".")
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go build failed in %s: %w", dir, err)
+	}
+
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return "", err
+	}
+	cliVersion, err := utils.ReportedCLIVersion(tmp)
+	if err != nil {
+		return "", fmt.Errorf("the build of %s is not a working JFrog CLI: %w", dir, err)
+	}
+
+	// Renaming replaces the directory entry, so hard links shared with other versions stay intact
+	targetBin := filepath.Join(targetDir, utils.BinaryName)
+	return cliVersion, os.Rename(tmp, targetBin)
+}
+
+// gitState returns the HEAD commit of a git checkout and whether it has uncommitted changes.
+// Both are empty when dir is not a git checkout or git is not available.
+func gitState(dir string) (string, bool) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", false
+	}
+	commit := strings.TrimSpace(string(out))
+
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		return commit, false
+	}
+	return commit, strings.TrimSpace(string(status)) != ""
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestLinkRejectsConflictingModes(t *testing.T) {
+	useTestRoot(t)
+	for line, want := range map[string]string{
+		"jfvm link --name dev":                             "Please provide --from <binary> or --build <repo-path>",
+		"jfvm link --name dev --from jf --build .":         "--from and --build cannot be used together",
+		"jfvm link --name dev --from jf --symlink --watch": "--watch is not needed with --symlink: the link always points at the current binary",
+		"jfvm link --name dev --build . --watch":           "--symlink and --watch only apply to --from",
+		"jfvm link --name dev --from /no/such/jf":          "no such file: /no/such/jf",
+	} {
+		if err := runCommandLine(t, line); err == nil || err.Error() != want {
+			t.Errorf("%s: got %v, want %q", line, err, want)
+		}
+	}
+	if installed, _ := utils.ListInstalledVersions(); len(installed) > 0 {
+		t.Errorf("versions were created: %v", installed)
+	}
+}
+
+func TestLinkCopiesAndSymlinks(t *testing.T) {
+	useTestRoot(t)
+	from := fakeCLIBinary(t, "2.75.0-dev")
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm link --name copied --from "+from); err != nil {
+			t.Fatal(err)
+		}
+		if err := runCommandLine(t, "jfvm link --name linked --symlink --from "+from); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	copied := filepath.Join(utils.JfvmVersions, "copied", utils.BinaryName)
+	if info, err := os.Lstat(copied); err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		t.Errorf("copied binary is not a regular executable: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(utils.JfvmVersions, "linked", utils.BinaryName)); err != nil || target != from {
+		t.Errorf("linked binary points at %q (%v), want %s", target, err, from)
+	}
+
+	for name, symlink := range map[string]bool{"copied": false, "linked": true} {
+		meta, err := utils.LoadVersionMetadata(name)
+		if err != nil || meta == nil {
+			t.Fatalf("%s has no metadata: %v", name, err)
+		}
+		if meta.Source != utils.SourceLink || meta.From != from || meta.Symlink != symlink || meta.CLIVersion != "2.75.0-dev" {
+			t.Errorf("%s metadata is %+v", name, meta)
+		}
+	}
+}
+
+func TestLinkRefusesShimAndItself(t *testing.T) {
+	useTestRoot(t)
+	from := fakeCLIBinary(t, "2.75.0-dev")
+	shim := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	if err := os.MkdirAll(utils.JfvmShim, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(from, shim); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	if err := runCommandLine(t, "jfvm link --name dev --from "+shim); err == nil || err.Error() != shim+" is the jfvm shim; link the real jf binary instead" {
+		t.Errorf("linking the shim: got %v", err)
+	}
+
+	// The shim reached through a symlink is still the shim
+	alias := filepath.Join(t.TempDir(), utils.BinaryName)
+	if err := os.Symlink(shim, alias); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCommandLine(t, "jfvm link --name dev --symlink --from "+alias); err == nil {
+		t.Error("linked the shim through a symlink")
+	}
+
+	installTestVersion(t, "2.70.0")
+	own := filepath.Join(utils.JfvmVersions, "2.70.0", utils.BinaryName)
+	if err := runCommandLine(t, "jfvm link --name 2.70.0 --from "+own); err == nil || err.Error() != own+" already is the binary of version 2.70.0" {
+		t.Errorf("linking a version onto itself: got %v", err)
+	}
+}
@@ -28,9 +28,45 @@ var List = &cli.Command{
 				if version == current {
 					mark = " (current)"
 				}
-				fmt.Printf(" - %s%s\n", version, mark)
+				fmt.Printf(" - %s%s%s\n", version, mark, describeLinkedVersion(version))
 			}
 		}
 		return nil
 	},
 }
+
+// describeLinkedVersion tells where a linked or locally built version came from.
+func describeLinkedVersion(version string) string {
+	meta, err := utils.LoadVersionMetadata(version)
+	if err != nil || meta == nil {
+		return ""
+	}
+	reported := ""
+	if meta.CLIVersion != "" {
+		reported = ", jf " + meta.CLIVersion
+	}
+	switch {
+	case meta.Source == utils.SourceGit:
+		commit := meta.GitCommit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		return fmt.Sprintf(" [source %s@%s @ %s%s]", meta.Repo, meta.GitRef, commit, reported)
+	case meta.Source == utils.SourceBuild:
+		commit := meta.GitCommit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if meta.GitDirty {
+			commit += ", dirty"
+		}
+		if commit == "" {
+			return fmt.Sprintf(" [built from %s%s]", meta.Repo, reported)
+		}
+		return fmt.Sprintf(" [built from %s @ %s%s]", meta.Repo, commit, reported)
+	case meta.Symlink:
+		return fmt.Sprintf(" [symlink to %s%s]", meta.From, reported)
+	default:
+		return fmt.Sprintf(" [copied from %s%s]", meta.From, reported)
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestHTTPCallString(t *testing.T) {
+	for call, want := range map[utils.HTTPCall]string{
+		{Method: "GET", Host: "example.jfrog.io", Path: "/api/system/ping", Status: 200}:                       "GET example.jfrog.io/api/system/ping → 200",
+		{Method: "GET", Host: "example.jfrog.io", Path: "/api/search", Query: "name=a", Status: 200}:           "GET example.jfrog.io/api/search?name=a → 200",
+		{Method: "POST", Host: "example.jfrog.io", Path: "/api/aql", Status: 502, Note: utils.CallNotRecorded}: "POST example.jfrog.io/api/aql → 502 (not recorded)",
+	} {
+		if got := call.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestNetworkChannelDiffsCallSequences(t *testing.T) {
+	ping := utils.HTTPCall{Method: "GET", Host: "example.jfrog.io", Path: "/api/system/ping", Status: 200}
+	version := utils.HTTPCall{Method: "GET", Host: "example.jfrog.io", Path: "/api/system/version", Status: 200}
+	results := []ExecutionResult{
+		{Version: "2.70.0", Output: "OK", Calls: []utils.HTTPCall{ping}},
+		{Version: "2.71.0", Output: "OK", Calls: []utils.HTTPCall{version, ping}},
+	}
+	opts := CompareOptions{Channels: []string{ChannelStdout, ChannelNetwork}}
+	report := buildCompareReport(groupResults("rt ping", "2.70.0", results, opts), opts)
+	if len(report.Diffs) != 1 || !report.Diffs[0].CallsDiffer || report.Diffs[0].Summary != "network calls differ in 1 hunks" {
+		t.Fatalf("diffs are %+v", report.Diffs)
+	}
+	if text := diffText(report.Diffs[0]); !strings.Contains(text, "network calls:\n") || !strings.Contains(text, "+GET example.jfrog.io/api/system/version → 200\n") {
+		t.Errorf("diff text is %q", text)
+	}
+
+	// Without the network channel the calls don't count
+	opts.Channels = []string{ChannelStdout}
+	if report := buildCompareReport(groupResults("rt ping", "2.70.0", results, opts), opts); !report.Identical {
+		t.Error("calls counted although the network channel was not selected")
+	}
+}
+
+func TestNormalizeResultNormalizesCalls(t *testing.T) {
+	normalizer, err := utils.NormalizationRules{Builtins: []string{"uuids"}}.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ExecutionResult{Calls: []utils.HTTPCall{{
+		Method: "GET",
+		Host:   "example.jfrog.io",
+		Path:   "/api/builds/3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+		Query:  "session=3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+	}}}
+	normalizeResult(&result, normalizer)
+	if call := result.Calls[0]; call.Path != "/api/builds/<UUID>" || call.Query != "session=<UUID>" {
+		t.Errorf("call normalized to %+v", call)
+	}
+}
+
+func TestCompareNetDiff(t *testing.T) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl is not installed")
+	}
+	useTestRoot(t)
+	t.Setenv("JFROG_CLI_HOME_DIR", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	// curl takes the proxy from the environment jf would use
+	fetch := `curl -s "` + server.URL + `/api/system/$1"; echo`
+	installTestScript(t, "2.70.0", fetch)
+	installTestScript(t, "2.71.0", `curl -s "`+server.URL+`/api/system/version" > /dev/null; `+fetch)
+
+	var err error
+	stdout := captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm compare --net-diff --format json 2.70.0 2.71.0 -- ping")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report CompareReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout)
+	}
+	if len(report.Diffs) != 1 || report.Diffs[0].Summary != "network calls differ in 1 hunks" {
+		t.Fatalf("diffs are %+v", report.Diffs)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	if calls := report.Results[0].Calls; len(calls) != 1 || calls[0].String() != "GET "+host+"/api/system/ping → 200" {
+		t.Errorf("2.70.0 calls are %+v", calls)
+	}
+
+	if err := runCommandLine(t, "jfvm compare --rerecord 2.70.0 2.71.0 -- ping"); err == nil || err.Error() != "--rerecord needs --replay" {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var Prune = &cli.Command{
+	Name:        "prune",
+	Usage:       descriptions.Prune.Usage,
+	Description: descriptions.Prune.Format(),
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "days",
+			Usage: "Keep versions used within this many days according to history",
+			Value: 30,
+		},
+		&cli.IntFlag{
+			Name:  "keep-latest",
+			Usage: "Always keep the N newest installed release versions",
+			Value: 0,
+		},
+		&cli.BoolFlag{
+			Name:  "include-linked",
+			Usage: "Also prune linked local builds, which cannot be re-downloaded",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only show what would be removed",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "purge",
+			Usage: "Delete release versions permanently instead of moving them to the trash",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output",
+			Value: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("no-color") {
+			color.NoColor = true
+		}
+
+		installed, err := utils.ListInstalledVersions()
+		if err != nil {
+			return err
+		}
+		if len(installed) == 0 {
+			fmt.Println("📭 No versions installed.")
+			return nil
+		}
+
+		keep := pruneKeepReasons(installed, c.Int("days"), c.Int("keep-latest"), c.Bool("include-linked"))
+		return displayPrune(installed, keep, c.Bool("dry-run"), c.Bool("purge"))
+	},
+}
+
+// pruneKeepReasons maps each version that must survive a prune to the reasons it is kept.
+func pruneKeepReasons(installed []string, days, keepLatest int, includeLinked bool) map[string][]string {
+	keep := make(map[string][]string)
+	isInstalled := make(map[string]bool)
+	for _, version := range installed {
+		isInstalled[version] = true
+	}
+	add := func(version, reason string) {
+		if isInstalled[version] {
+			keep[version] = append(keep[version], reason)
+		}
+	}
+
+	if current := utils.GetCurrentVersion(); current != "" {
+		add(current, "current")
+	}
+
+	if names, targets, err := utils.ListAliases(); err == nil {
+		for _, name := range names {
+			add(targets[name], fmt.Sprintf("alias '%s'", name))
+		}
+	}
+
+	projectFiles := utils.KnownProjectFiles()
+	if _, err := os.Stat(utils.ProjectFile); err == nil {
+		projectFiles = append(projectFiles, utils.ProjectFile)
+	}
+	if manifest, err := utils.FindProjectManifest(); err == nil && manifest != nil {
+		projectFiles = append(projectFiles, manifest.Path)
+	}
+	for _, path := range projectFiles {
+		for _, version := range utils.ProjectFileVersions(path) {
+			add(version, fmt.Sprintf("referenced by %s", path))
+		}
+	}
+
+	if days > 0 {
+		cutoff := time.Now().AddDate(0, 0, -days)
+		lastUsed := make(map[string]time.Time)
+		if entries, err := loadHistory(utils.JfvmHistory); err == nil {
+			for _, entry := range entries {
+				if entry.Timestamp.After(lastUsed[entry.Version]) {
+					lastUsed[entry.Version] = entry.Timestamp
+				}
+			}
+		}
+		for version, used := range lastUsed {
+			if used.After(cutoff) {
+				add(version, fmt.Sprintf("used %s", used.Format("2006-01-02")))
+			}
+		}
+	}
+
+	if keepLatest > 0 {
+		var releases []string
+		for _, version := range installed {
+			if utils.IsReleaseVersion(version) {
+				releases = append(releases, version)
+			}
+		}
+		// installed is sorted ascending, so the newest releases are at the end
+		for i := len(releases) - 1; i >= 0 && i >= len(releases)-keepLatest; i-- {
+			add(releases[i], fmt.Sprintf("one of the %d newest", keepLatest))
+		}
+	}
+
+	if !includeLinked {
+		for _, version := range installed {
+			if !utils.IsReleaseVersion(version) {
+				add(version, "linked build")
+			}
+		}
+	}
+
+	return keep
+}
+
+func displayPrune(installed []string, keep map[string][]string, dryRun, purge bool) error {
+	var (
+		greenColor  = color.New(color.FgGreen)
+		redColor    = color.New(color.FgRed)
+		yellowColor = color.New(color.FgYellow, color.Bold)
+	)
+
+	fmt.Printf("🧹 PRUNING INSTALLED VERSIONS\n")
+	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n\n")
+
+	var remove []string
+	for _, version := range installed {
+		if reasons, ok := keep[version]; ok {
+			sort.Strings(reasons)
+			fmt.Printf("%s %-15s %s\n", greenColor.Sprint("keep  "), version, strings.Join(reasons, ", "))
+		} else {
+			remove = append(remove, version)
+		}
+	}
+
+	// Sizes must be measured while every version is still in place
+	reclaimed, trashed := reclaimableSizes(remove, purge)
+	for _, version := range remove {
+		size, _ := utils.DirSize(filepath.Join(utils.JfvmVersions, version))
+		if !dryRun {
+			if _, err := removeVersion(version, purge); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", version, err)
+			}
+		}
+		fmt.Printf("%s %-15s %s\n", redColor.Sprint("remove"), version, utils.FormatBytes(size))
+	}
+
+	fmt.Printf("\n─────────────────────────────────────────────────────────────────────────────────────\n")
+	if len(remove) == 0 {
+		fmt.Println("✅ Nothing to prune.")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("📋 Would remove %d of %d versions and reclaim %s\n", len(remove), len(installed), yellowColor.Sprint(utils.FormatBytes(reclaimed)))
+	} else {
+		fmt.Printf("🗑️  Removed %d of %d versions and reclaimed %s\n", len(remove), len(installed), yellowColor.Sprint(utils.FormatBytes(reclaimed-trashed)))
+		if trashed > 0 {
+			fmt.Printf("♻️  %s is held in the trash until it expires; run 'jfvm trash empty' to free it now\n", utils.FormatBytes(trashed))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestPruneKeepReasons(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installed := []string{"my-build", "2.60.0", "2.65.0", "2.70.0", "2.71.0", "2.72.0", "2.73.0", "2.74.0"}
+	for _, version := range installed {
+		installTestVersion(t, version)
+	}
+	if err := utils.SetActiveVersion("2.74.0", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.WriteAlias("prod", "2.71.0"); err != nil {
+		t.Fatal(err)
+	}
+	// A project elsewhere on the machine that selected a range
+	project := filepath.Join(t.TempDir(), utils.ProjectFile)
+	if err := os.WriteFile(project, []byte("~2.65"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	utils.RecordProjectFile(project)
+	if err := saveHistory(utils.JfvmHistory, []HistoryEntry{
+		{Version: "2.72.0", Timestamp: time.Now().AddDate(0, 0, -3)},
+		{Version: "2.60.0", Timestamp: time.Now().AddDate(0, 0, -90)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	keep := pruneKeepReasons(installed, 30, 2, false)
+	want := map[string][]string{
+		"my-build": {"linked build"},
+		"2.65.0":   {"referenced by " + project},
+		"2.71.0":   {"alias 'prod'"},
+		"2.72.0":   {"used " + time.Now().AddDate(0, 0, -3).Format("2006-01-02")},
+		"2.73.0":   {"one of the 2 newest"},
+		"2.74.0":   {"current", "one of the 2 newest"},
+	}
+	if !reflect.DeepEqual(keep, want) {
+		t.Errorf("got %v\nwant %v", keep, want)
+	}
+
+	// Without the history window and with linked builds, only references keep versions
+	keep = pruneKeepReasons(installed, 0, 0, true)
+	for _, version := range []string{"my-build", "2.60.0", "2.70.0", "2.72.0", "2.73.0"} {
+		if reasons, ok := keep[version]; ok {
+			t.Errorf("%s kept for %v", version, reasons)
+		}
+	}
+}
+
+func TestPruneDryRunAndTrash(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	for _, version := range []string{"2.70.0", "2.71.0"} {
+		installTestVersion(t, version)
+	}
+	if err := utils.SetActiveVersion("2.71.0", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm prune --dry-run --no-color"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "Would remove 1 of 2 versions") || utils.CheckVersionExists("2.70.0") != nil {
+		t.Fatalf("dry run removed a version or reported wrongly:\n%s", stdout)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm prune --no-color"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if utils.CheckVersionExists("2.70.0") == nil || utils.CheckVersionExists("2.71.0") != nil {
+		t.Error("prune removed the wrong version")
+	}
+	if entries, _ := utils.ListTrash(); len(entries) != 1 || entries[0].Version != "2.70.0" {
+		t.Errorf("trash holds %v, want 2.70.0", entries)
+	}
+}
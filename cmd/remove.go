@@ -4,26 +4,248 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
 	"github.com/urfave/cli/v2"
 )
 
 var Remove = &cli.Command{
-	Name:      "remove",
-	Usage:     "Remove an installed JFrog CLI version",
-	ArgsUsage: "[version]",
+	Name:        "remove",
+	Usage:       "Remove installed JFrog CLI versions",
+	ArgsUsage:   "[version, glob or range...]",
+	Description: descriptions.Remove.Format(),
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "older-than",
+			Usage: "Remove all release versions older than this version",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Don't ask for confirmation",
+			Value:   false,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only show which versions would be removed",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Also remove the active version and versions aliases point at",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "purge",
+			Usage: "Delete release versions permanently instead of moving them to the trash",
+			Value: false,
+		},
+	},
 	Action: func(c *cli.Context) error {
-		if c.Args().Len() != 1 {
-			return cli.Exit("Please provide a version to remove", 1)
+		patterns := c.Args().Slice()
+		olderThan := c.String("older-than")
+		if len(patterns) == 0 && olderThan == "" {
+			return cli.Exit("Please provide a version, pattern or --older-than to remove", 1)
+		}
+
+		installed, err := utils.ListInstalledVersions()
+		if err != nil {
+			return err
+		}
+
+		matched, err := matchVersions(patterns, installed)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if olderThan != "" {
+			older, err := versionsOlderThan(olderThan, installed)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			matched = mergeVersions(installed, matched, older)
+		}
+		if len(matched) == 0 {
+			if len(patterns) == 1 && olderThan == "" {
+				return fmt.Errorf("version %s is not installed", patterns[0])
+			}
+			return cli.Exit("No installed versions match", 1)
 		}
-		version := c.Args().Get(0)
-		dir := filepath.Join(utils.JfvmVersions, version)
 
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			return fmt.Errorf("version %s is not installed", version)
+		targets := excludeProtected(matched, c.Bool("force"))
+		if len(targets) == 0 {
+			return cli.Exit("Nothing to remove: all matching versions are protected. Use --force to remove them anyway", 1)
 		}
 
-		return os.RemoveAll(dir)
+		return removeVersions(targets, c.Bool("yes"), c.Bool("dry-run"), c.Bool("purge"))
 	},
 }
+
+// matchVersions selects installed versions by exact name, glob (2.6*) or range (^2.70, >=2.60 <2.70).
+func matchVersions(patterns, installed []string) ([]string, error) {
+	var matched []string
+	for _, pattern := range patterns {
+		var hits []string
+		switch {
+		case slices.Contains(installed, pattern):
+			hits = []string{pattern}
+		case strings.ContainsAny(pattern, "*?["):
+			for _, version := range installed {
+				ok, err := filepath.Match(pattern, version)
+				if err != nil {
+					return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+				}
+				if ok {
+					hits = append(hits, version)
+				}
+			}
+		case utils.IsVersionRange(pattern):
+			versionRange, _ := utils.ParseVersionRange(pattern)
+			for _, version := range installed {
+				if versionRange.Matches(version) {
+					hits = append(hits, version)
+				}
+			}
+		}
+		if len(hits) == 0 && len(patterns) > 1 {
+			fmt.Printf("⚠️  No installed versions match '%s'\n", pattern)
+		}
+		matched = mergeVersions(installed, matched, hits)
+	}
+	return matched, nil
+}
+
+func versionsOlderThan(limit string, installed []string) ([]string, error) {
+	limitVersion, err := utils.ParseVersion(limit)
+	if err != nil {
+		return nil, fmt.Errorf("--older-than needs a version: %w", err)
+	}
+	var older []string
+	for _, version := range installed {
+		if !utils.IsReleaseVersion(version) {
+			continue
+		}
+		if v, _ := utils.ParseVersion(version); v.Compare(limitVersion) < 0 {
+			older = append(older, version)
+		}
+	}
+	return older, nil
+}
+
+// mergeVersions returns the union of the given sets in the order of installed.
+func mergeVersions(installed []string, sets ...[]string) []string {
+	selected := make(map[string]bool)
+	for _, set := range sets {
+		for _, version := range set {
+			selected[version] = true
+		}
+	}
+	var merged []string
+	for _, version := range installed {
+		if selected[version] {
+			merged = append(merged, version)
+		}
+	}
+	return merged
+}
+
+// excludeProtected drops the active version and aliased versions unless force is set.
+func excludeProtected(versions []string, force bool) []string {
+	current := utils.GetCurrentVersion()
+	var kept []string
+	for _, version := range versions {
+		aliases := utils.AliasesForVersion(version)
+		switch {
+		case version == current && !force:
+			fmt.Printf("🛡️  Keeping %s: it is the active version\n", version)
+		case len(aliases) > 0 && !force:
+			fmt.Printf("🛡️  Keeping %s: aliases %s point at it\n", version, strings.Join(aliases, ", "))
+		default:
+			if version == current {
+				fmt.Printf("⚠️  %s is the active version; 'jf' will not work until you run 'jfvm use'\n", version)
+			}
+			if len(aliases) > 0 {
+				fmt.Printf("⚠️  Aliases %s will point at a removed version; run 'jfvm alias prune' to clean them up\n", strings.Join(aliases, ", "))
+			}
+			kept = append(kept, version)
+		}
+	}
+	return kept
+}
+
+// removeVersions shows what will be removed, asks for confirmation unless yes is set, and removes.
+func removeVersions(versions []string, yes, dryRun, purge bool) error {
+	for _, version := range versions {
+		size, _ := utils.DirSize(filepath.Join(utils.JfvmVersions, version))
+		fmt.Printf(" - %-15s %s\n", version, utils.FormatBytes(size))
+	}
+	total, _ := reclaimableSizes(versions, false)
+
+	if dryRun {
+		fmt.Printf("📋 Would remove %d versions and free %s\n", len(versions), utils.FormatBytes(total))
+		return nil
+	}
+	if !yes && !utils.Confirm(fmt.Sprintf("Remove %d versions and free %s?", len(versions), utils.FormatBytes(total))) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	trashed := 0
+	for _, version := range versions {
+		inTrash, err := removeVersion(version, purge)
+		if err != nil {
+			return fmt.Errorf("failed to remove %s: %w", version, err)
+		}
+		if inTrash {
+			trashed++
+		}
+	}
+	fmt.Printf("🗑️  Removed %d versions.\n", len(versions))
+	if trashed > 0 {
+		fmt.Printf("♻️  %d moved to the trash; bring them back with 'jfvm restore <version>'\n", trashed)
+	}
+	return nil
+}
+
+// reclaimableSizes returns how many bytes removing versions frees, and how much of that is
+// held in the trash instead of freed right away. Binaries hard-linked from versions that
+// stay installed, or from the trash, free nothing.
+func reclaimableSizes(versions []string, purge bool) (int64, int64) {
+	installed, _ := utils.ListInstalledVersions()
+	keep := []string{utils.JfvmTrash}
+	for _, version := range installed {
+		if !slices.Contains(versions, version) {
+			keep = append(keep, filepath.Join(utils.JfvmVersions, version))
+		}
+	}
+
+	var all, deleted, trashed []string
+	for _, version := range versions {
+		dir := filepath.Join(utils.JfvmVersions, version)
+		all = append(all, dir)
+		if purge && utils.IsReleaseVersion(version) {
+			deleted = append(deleted, dir)
+		} else {
+			trashed = append(trashed, dir)
+		}
+	}
+
+	total, _ := utils.ReclaimableSize(all, keep)
+	freed, _ := utils.ReclaimableSize(deleted, append(keep, trashed...))
+	return total, total - freed
+}
+
+// removeVersion moves an installed version to the trash and reports whether it did.
+// With purge, release versions are deleted permanently; linked builds cannot be
+// re-downloaded, so they always go to the trash.
+func removeVersion(version string, purge bool) (bool, error) {
+	if purge && utils.IsReleaseVersion(version) {
+		return false, os.RemoveAll(filepath.Join(utils.JfvmVersions, version))
+	}
+	if _, err := utils.MoveToTrash(version); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestMatchVersions(t *testing.T) {
+	installed := []string{"my-build", "2.60.0", "2.65.1", "2.70.0", "2.71.2", "3.0.0"}
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"2.70.0"}, []string{"2.70.0"}},
+		{[]string{"2.6*"}, []string{"2.60.0", "2.65.1"}},
+		{[]string{"^2.70"}, []string{"2.70.0", "2.71.2"}},
+		{[]string{">=2.65 <2.71"}, []string{"2.65.1", "2.70.0"}},
+		{[]string{"my-*", "3.0.0", "2.60.0"}, []string{"my-build", "2.60.0", "3.0.0"}},
+		{[]string{"2.99.0"}, nil},
+	}
+	for _, test := range tests {
+		var got []string
+		captureStdout(t, func() {
+			var err error
+			if got, err = matchVersions(test.patterns, installed); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%v: got %v, want %v", test.patterns, got, test.want)
+		}
+	}
+	if _, err := matchVersions([]string{"2.[6"}, installed); err == nil {
+		t.Error("a malformed glob was accepted")
+	}
+}
+
+func TestVersionsOlderThanSkipsLinkedBuilds(t *testing.T) {
+	older, err := versionsOlderThan("2.70", []string{"my-build", "2.60.0", "2.69.9", "2.70.0", "2.71.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(older, []string{"2.60.0", "2.69.9"}) {
+		t.Errorf("got %v", older)
+	}
+	if _, err := versionsOlderThan("soon", nil); err == nil {
+		t.Error("--older-than accepted a name")
+	}
+}
+
+func TestRemoveProtectsActiveAndAliasedVersions(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0", "2.73.0"} {
+		installTestVersion(t, version)
+	}
+	if err := utils.SetActiveVersion("2.73.0", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.WriteAlias("prod", "2.72.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm remove --dry-run ^2.70"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "Would remove 2 versions") || !strings.Contains(stdout, "Keeping 2.72.0: aliases prod point at it") {
+		t.Errorf("unexpected dry run:\n%s", stdout)
+	}
+	if installed, _ := utils.ListInstalledVersions(); len(installed) != 4 {
+		t.Fatalf("dry run removed versions: %v", installed)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm remove --yes --older-than 2.72.0 2.73.0"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if installed, _ := utils.ListInstalledVersions(); !slices.Equal(installed, []string{"2.72.0", "2.73.0"}) {
+		t.Errorf("installed after remove: %v", installed)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm remove --yes --force --purge 2.72.0"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if utils.CheckVersionExists("2.72.0") == nil {
+		t.Error("--force did not remove the aliased version")
+	}
+	if entries, _ := utils.ListTrash(); len(entries) != 2 {
+		t.Errorf("trash holds %v, want the two versions removed without --purge", entries)
+	}
+}
+
+func TestClearKeepsPatterns(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	for _, version := range []string{"my-build", "2.70.0", "2.71.0", "2.72.0"} {
+		installTestVersion(t, version)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm clear --yes --keep 2.72.0,my-*"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if installed, _ := utils.ListInstalledVersions(); !slices.Equal(installed, []string{"my-build", "2.72.0"}) {
+		t.Errorf("installed after clear: %v", installed)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
+	"github.com/urfave/cli/v2"
+)
+
+var Rollback = &cli.Command{
+	Name:        "rollback",
+	Usage:       descriptions.Rollback.Usage,
+	ArgsUsage:   "[N]",
+	Description: descriptions.Rollback.Format(),
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Note recorded with the switch in the journal",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		n := 1
+		if err := misplacedFlagError(c); err != nil {
+			return err
+		}
+		if c.Args().Len() > 1 {
+			return cli.Exit("Usage: jfvm rollback [--reason <text>] [N]", 1)
+		}
+		if c.Args().Len() == 1 {
+			parsed, err := strconv.Atoi(c.Args().Get(0))
+			if err != nil || parsed < 1 {
+				return cli.Exit("N must be a positive number of switches to go back", 1)
+			}
+			n = parsed
+		}
+
+		version, err := versionBeforeSwitch(n)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+
+		if err := utils.CheckVersionExists(version); err != nil {
+			if !utils.IsReleaseVersion(version) {
+				return fmt.Errorf("version %s is no longer installed and cannot be downloaded: %w", version, err)
+			}
+			fmt.Printf("Version %s not found locally. Installing...\n", version)
+			if err := internal.DownloadAndInstall(version); err != nil {
+				return fmt.Errorf("auto-install failed: %w", err)
+			}
+		}
+
+		if err := utils.SetActiveVersion(version, fmt.Sprintf("rollback %d", n), c.String("reason")); err != nil {
+			return err
+		}
+		fmt.Printf("⏪ Rolled back to %s\n", version)
+		return nil
+	},
+}
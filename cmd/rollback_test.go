@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestRollbackReasonExample(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0", "2.73.0"} {
+		installTestVersion(t, version)
+		if err := utils.SetActiveVersion(version, "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	example := documentedExample(t, descriptions.Rollback, "--reason")
+	if err := runCommandLine(t, example); err != nil {
+		t.Fatalf("%s: %v", example, err)
+	}
+
+	if current := utils.GetCurrentVersion(); current != "2.70.0" {
+		t.Errorf("active version is %s, want 2.70.0", current)
+	}
+	entries, err := utils.LoadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := entries[len(entries)-1]
+	if last.Reason != "use latest broke the release pipeline" {
+		t.Errorf("journal reason is %q", last.Reason)
+	}
+}
+
+func TestVersionBeforeSwitch(t *testing.T) {
+	useTestRoot(t)
+	if _, err := versionBeforeSwitch(1); err == nil || err.Error() != "no version switches recorded yet" {
+		t.Errorf("empty journal: got %v", err)
+	}
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0"} {
+		if err := utils.SetActiveVersion(version, "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for n, want := range map[int]string{1: "2.71.0", 2: "2.70.0"} {
+		if got, err := versionBeforeSwitch(n); err != nil || got != want {
+			t.Errorf("switch #%d: got %s, %v, want %s", n, got, err, want)
+		}
+	}
+	if _, err := versionBeforeSwitch(3); err == nil || err.Error() != "no version was active before switch #3" {
+		t.Errorf("first switch: got %v", err)
+	}
+	if _, err := versionBeforeSwitch(4); err == nil || err.Error() != "only 3 version switches are recorded" {
+		t.Errorf("beyond the journal: got %v", err)
+	}
+}
+
+func TestRollbackRejectsInvalidN(t *testing.T) {
+	useTestRoot(t)
+	for _, line := range []string{"jfvm rollback 0", "jfvm rollback two", "jfvm rollback 1 2", "jfvm rollback 1 --reason x"} {
+		if err := runCommandLine(t, line); err == nil {
+			t.Errorf("%s was accepted", line)
+		}
+	}
+}
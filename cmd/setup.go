@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	profileBlockStart = "# >>> jfvm >>>"
+	profileBlockEnd   = "# <<< jfvm <<<"
+)
+
+// shimMarker is the recursion guard variable every shim reads, so its name is part of every
+// shim binary and of no JFrog CLI.
+const shimMarker = "JFVM_SHIM_DEPTH"
+
+// legacyPathLine is what the Makefile bootstrap target used to append to shell profiles.
+const legacyPathLine = `export PATH="$HOME/.jfvm/shim:$PATH"`
+
+type shellProfile struct {
+	Shell string
+	Path  string
+	Lines []string
+}
+
+var Setup = &cli.Command{
+	Name:        "setup",
+	Usage:       descriptions.Setup.Usage,
+	Description: descriptions.Setup.Format(),
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "shell",
+			Usage: "Shell to configure: bash, zsh, fish, sh (default: detected from $SHELL)",
+		},
+		&cli.StringFlag{
+			Name:  "shim",
+			Usage: "Path to a jf shim binary to install instead of the bundled one",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-shell",
+			Usage: "Only install the shim, don't edit shell profiles",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "uninstall",
+			Usage: "Remove the shim and the jfvm block from all shell profiles",
+			Value: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("uninstall") {
+			return uninstallSetup()
+		}
+
+		if err := installShim(c.String("shim")); err != nil {
+			return err
+		}
+
+		if c.Bool("skip-shell") {
+			return nil
+		}
+
+		shells := commaSeparated(c.StringSlice("shell"))
+		if len(shells) == 0 {
+			shells = []string{detectShell()}
+		}
+		for _, shell := range shells {
+			profile, err := profileForShell(shell)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			changed, err := writeProfileBlock(profile)
+			if err != nil {
+				if err := warnUnterminatedBlock(profile.Path, err); err != nil {
+					return fmt.Errorf("failed to update %s: %w", profile.Path, err)
+				}
+				continue
+			}
+			if changed {
+				fmt.Printf("✅ Added %s to PATH in %s\n", utils.JfvmShim, profile.Path)
+			} else {
+				fmt.Printf("✅ %s is already configured\n", profile.Path)
+			}
+		}
+
+		fmt.Println("🔁 Restart your shell or source the updated profile to apply.")
+		return nil
+	},
+}
+
+// findShimSource returns the shim binary to install and a description of where it came from.
+// An explicit path wins, then the shim embedded at build time, then a jf shim built alongside
+// the running jfvm executable (as `make install` lays them out). Files that are not shim
+// builds are never used.
+func findShimSource(explicit string) ([]byte, string, error) {
+	if explicit != "" {
+		data, err := os.ReadFile(explicit)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read shim %s: %w", explicit, err)
+		}
+		if !isShimBinary(data) {
+			return nil, "", fmt.Errorf("%s is not a jfvm shim build", explicit)
+		}
+		return data, explicit, nil
+	}
+
+	if len(internal.EmbeddedShim) > 0 {
+		return internal.EmbeddedShim, "embedded shim", nil
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exeDir := filepath.Dir(exe)
+		for _, candidate := range []string{
+			filepath.Join(exeDir, utils.BinaryName),
+			filepath.Join(exeDir, utils.ShimDir, utils.BinaryName),
+		} {
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+				data, err := os.ReadFile(candidate)
+				if err != nil {
+					return nil, "", err
+				}
+				// A jf next to jfvm, like in Homebrew's bin, is often the real JFrog CLI
+				if isShimBinary(data) {
+					return data, candidate, nil
+				}
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("no jf shim available: this jfvm was built without an embedded shim; build it with 'make build' or pass --shim <path>")
+}
+
+// isShimBinary reports whether data is a build of the jfvm shim.
+func isShimBinary(data []byte) bool {
+	return bytes.Contains(data, []byte(shimMarker))
+}
+
+func installShim(explicit string) error {
+	data, source, err := findShimSource(explicit)
+	if err != nil {
+		return err
+	}
+
+	shimPath := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	if existing, err := os.ReadFile(shimPath); err == nil && string(existing) == string(data) {
+		fmt.Printf("✅ Shim at %s is up to date\n", shimPath)
+		return nil
+	}
+
+	if err := os.MkdirAll(utils.JfvmShim, 0755); err != nil {
+		return fmt.Errorf("failed to create shim directory: %w", err)
+	}
+
+	// Write next to the target and rename so a running shim is never left half-written
+	tmp, err := os.CreateTemp(utils.JfvmShim, ".jf-*")
+	if err != nil {
+		return fmt.Errorf("failed to install shim: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to install shim: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to install shim: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("chmod failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), shimPath); err != nil {
+		return fmt.Errorf("failed to install shim: %w", err)
+	}
+
+	fmt.Printf("✅ Installed shim from %s to %s\n", source, shimPath)
+	return nil
+}
+
+func detectShell() string {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	switch shell {
+	case "bash", "zsh", "fish":
+		return shell
+	default:
+		return "sh"
+	}
+}
+
+// shimPathForProfile prefers $HOME-relative paths so profiles stay valid if the home directory moves.
+func shimPathForProfile() string {
+	if rel, err := filepath.Rel(utils.HomeDir, utils.JfvmShim); err == nil && !strings.HasPrefix(rel, "..") {
+		return "$HOME/" + filepath.ToSlash(rel)
+	}
+	return utils.JfvmShim
+}
+
+func profileForShell(shell string) (shellProfile, error) {
+	shimPath := shimPathForProfile()
+	posixLines := []string{fmt.Sprintf(`export PATH="%s:$PATH"`, shimPath)}
+
+	switch shell {
+	case "bash":
+		return shellProfile{Shell: shell, Path: filepath.Join(utils.HomeDir, ".bashrc"), Lines: posixLines}, nil
+	case "zsh":
+		return shellProfile{Shell: shell, Path: filepath.Join(utils.HomeDir, ".zshrc"), Lines: posixLines}, nil
+	case "sh":
+		return shellProfile{Shell: shell, Path: filepath.Join(utils.HomeDir, ".profile"), Lines: posixLines}, nil
+	case "fish":
+		return shellProfile{
+			Shell: shell,
+			Path:  filepath.Join(utils.HomeDir, ".config", "fish", "config.fish"),
+			Lines: []string{
+				fmt.Sprintf(`if not contains "%s" $PATH`, shimPath),
+				fmt.Sprintf(`    set -gx PATH "%s" $PATH`, shimPath),
+				"end",
+			},
+		}, nil
+	default:
+		return shellProfile{}, fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish, sh)", shell)
+	}
+}
+
+func allShellProfiles() []shellProfile {
+	var profiles []shellProfile
+	for _, shell := range []string{"bash", "zsh", "sh", "fish"} {
+		profile, _ := profileForShell(shell)
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// errUnterminatedBlock means a profile has the start of a jfvm block but no end. Removing
+// it would take everything after the start with it, so the profile is left alone.
+var errUnterminatedBlock = errors.New("the jfvm block has no end marker")
+
+// stripProfileBlock removes the jfvm block and any line the old Makefile bootstrap appended.
+func stripProfileBlock(content string) (string, error) {
+	var kept []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == profileBlockStart:
+			inBlock = true
+		case trimmed == profileBlockEnd:
+			inBlock = false
+		case inBlock, trimmed == legacyPathLine:
+		default:
+			kept = append(kept, line)
+		}
+	}
+	if inBlock {
+		return content, errUnterminatedBlock
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+// warnUnterminatedBlock reports a profile that is left unchanged because its jfvm block is
+// broken, and lets other errors through.
+func warnUnterminatedBlock(path string, err error) error {
+	if !errors.Is(err, errUnterminatedBlock) {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Left %s unchanged: %v. Remove the lines from '%s' on by hand and run the command again.\n", path, err, profileBlockStart)
+	return nil
+}
+
+func writeProfileBlock(profile shellProfile) (bool, error) {
+	existing, err := os.ReadFile(profile.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	content, err := stripProfileBlock(string(existing))
+	if err != nil {
+		return false, err
+	}
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	content += profileBlockStart + "\n" + strings.Join(profile.Lines, "\n") + "\n" + profileBlockEnd + "\n"
+
+	if content == string(existing) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(profile.Path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(profile.Path, []byte(content), 0644)
+}
+
+func removeProfileBlock(profile shellProfile) (bool, error) {
+	existing, err := os.ReadFile(profile.Path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	content, err := stripProfileBlock(string(existing))
+	if err != nil {
+		return false, err
+	}
+	if content == string(existing) {
+		return false, nil
+	}
+	content = strings.TrimRight(content, "\n") + "\n"
+	if strings.TrimSpace(content) == "" {
+		content = ""
+	}
+	return true, os.WriteFile(profile.Path, []byte(content), 0644)
+}
+
+func uninstallSetup() error {
+	for _, profile := range allShellProfiles() {
+		changed, err := removeProfileBlock(profile)
+		if err != nil {
+			if err := warnUnterminatedBlock(profile.Path, err); err != nil {
+				return fmt.Errorf("failed to update %s: %w", profile.Path, err)
+			}
+			continue
+		}
+		if changed {
+			fmt.Printf("🗑️  Removed jfvm from %s\n", profile.Path)
+		}
+	}
+
+	shimPath := filepath.Join(utils.JfvmShim, utils.BinaryName)
+	if err := os.Remove(shimPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove shim: %w", err)
+	} else if err == nil {
+		fmt.Printf("🗑️  Removed shim %s\n", shimPath)
+	}
+	// Only succeeds when nothing else (e.g. a jfvm copy from `make install`) is left in there
+	_ = os.Remove(utils.JfvmShim)
+
+	fmt.Println("✅ jfvm setup removed. Installed versions in", utils.JfvmVersions, "were kept.")
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStripProfileBlock(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+		err                 error
+	}{
+		{"no block", "alias ll='ls -l'\n", "alias ll='ls -l'\n", nil},
+		{"block", "a\n# >>> jfvm >>>\nexport PATH=x\n# <<< jfvm <<<\nb\n", "a\nb\n", nil},
+		{"legacy line", "a\n" + legacyPathLine + "\nb", "a\nb", nil},
+		{"indented markers", "a\n  # >>> jfvm >>>\nx\n  # <<< jfvm <<<\n", "a\n", nil},
+		{"unterminated", "a\n# >>> jfvm >>>\nexport PATH=x\nalias ll='ls -l'\n", "a\n# >>> jfvm >>>\nexport PATH=x\nalias ll='ls -l'\n", errUnterminatedBlock},
+	}
+	for _, test := range tests {
+		got, err := stripProfileBlock(test.content)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.err)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWriteAndRemoveProfileBlock(t *testing.T) {
+	profile := shellProfile{Shell: "bash", Path: filepath.Join(t.TempDir(), ".bashrc"), Lines: []string{`export PATH="$HOME/.jfvm/shim:$PATH"`}}
+	if err := os.WriteFile(profile.Path, []byte("alias ll='ls -l'\n"+legacyPathLine+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := writeProfileBlock(profile); err != nil || !changed {
+		t.Fatalf("first write: changed %v, error %v", changed, err)
+	}
+	data, _ := os.ReadFile(profile.Path)
+	want := "alias ll='ls -l'\n\n" + profileBlockStart + "\n" + profile.Lines[0] + "\n" + profileBlockEnd + "\n"
+	if string(data) != want {
+		t.Errorf("profile is %q, want %q", data, want)
+	}
+	if changed, err := writeProfileBlock(profile); err != nil || changed {
+		t.Errorf("second write: changed %v, error %v", changed, err)
+	}
+
+	if changed, err := removeProfileBlock(profile); err != nil || !changed {
+		t.Fatalf("remove: changed %v, error %v", changed, err)
+	}
+	if data, _ := os.ReadFile(profile.Path); string(data) != "alias ll='ls -l'\n" {
+		t.Errorf("profile after removal is %q", data)
+	}
+}
+
+func TestWriteProfileBlockKeepsUnterminatedBlock(t *testing.T) {
+	profile := shellProfile{Shell: "zsh", Path: filepath.Join(t.TempDir(), ".zshrc"), Lines: []string{"export PATH=x"}}
+	broken := "# >>> jfvm >>>\nexport PATH=old\nalias ll='ls -l'\n"
+	if err := os.WriteFile(profile.Path, []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeProfileBlock(profile); !errors.Is(err, errUnterminatedBlock) {
+		t.Errorf("got %v, want %v", err, errUnterminatedBlock)
+	}
+	if _, err := removeProfileBlock(profile); !errors.Is(err, errUnterminatedBlock) {
+		t.Errorf("got %v, want %v", err, errUnterminatedBlock)
+	}
+	if data, _ := os.ReadFile(profile.Path); string(data) != broken {
+		t.Errorf("profile changed to %q", data)
+	}
+}
+
+func TestFindShimSourceRejectsOtherBinaries(t *testing.T) {
+	dir := t.TempDir()
+	cli, shim := filepath.Join(dir, "jf-cli"), filepath.Join(dir, "jf-shim")
+	if err := os.WriteFile(cli, []byte("#!/bin/sh\necho jf version 2.70.0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(shim, []byte("\x7fELF..."+shimMarker+"..."), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := findShimSource(cli); err == nil || !strings.Contains(err.Error(), "is not a jfvm shim build") {
+		t.Errorf("got %v for a JFrog CLI, want it rejected", err)
+	}
+	data, source, err := findShimSource(shim)
+	if err != nil || source != shim || !isShimBinary(data) {
+		t.Errorf("got %s, %v for a shim build", source, err)
+	}
+}
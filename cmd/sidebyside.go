@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/term"
+)
+
+const (
+	defaultDiffWidth = 120
+	minColumnWidth   = 20
+	lineNumberWidth  = 4
+	tabWidth         = 4
+)
+
+// sideRow is one row of a side-by-side diff. Changed rows pair a deleted line with the
+// inserted line that replaced it; fold rows stand for a run of hidden unchanged lines.
+type sideRow struct {
+	Kind        byte
+	Left, Right string
+	LeftNo      int
+	RightNo     int
+	Folded      int
+}
+
+const rowChanged = '~'
+
+// styledRune is one rune of a rendered cell with whether it is highlighted.
+type styledRune struct {
+	r         rune
+	highlight bool
+}
+
+// terminalWidth returns the width to render diffs in: the terminal's, then $COLUMNS.
+func terminalWidth() int {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultDiffWidth
+}
+
+// sideBySideRows aligns two outputs into rows, pairing runs of deleted and inserted lines.
+func sideBySideRows(output1, output2 string) []sideRow {
+	lines := lineDiff(output1, output2)
+	var rows []sideRow
+	leftNo, rightNo := 1, 1
+
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == lineEqual {
+			rows = append(rows, sideRow{Kind: lineEqual, Left: lines[i].Text, Right: lines[i].Text, LeftNo: leftNo, RightNo: rightNo})
+			leftNo++
+			rightNo++
+			i++
+			continue
+		}
+
+		var deleted, inserted []string
+		for ; i < len(lines) && lines[i].Kind != lineEqual; i++ {
+			if lines[i].Kind == lineDelete {
+				deleted = append(deleted, lines[i].Text)
+			} else {
+				inserted = append(inserted, lines[i].Text)
+			}
+		}
+		for j := 0; j < len(deleted) || j < len(inserted); j++ {
+			row := sideRow{}
+			switch {
+			case j < len(deleted) && j < len(inserted):
+				row = sideRow{Kind: rowChanged, Left: deleted[j], Right: inserted[j], LeftNo: leftNo, RightNo: rightNo}
+				leftNo++
+				rightNo++
+			case j < len(deleted):
+				row = sideRow{Kind: lineDelete, Left: deleted[j], LeftNo: leftNo}
+				leftNo++
+			default:
+				row = sideRow{Kind: lineInsert, Right: inserted[j], RightNo: rightNo}
+				rightNo++
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// foldRows replaces unchanged rows farther than context rows from any change with fold rows.
+// A negative context keeps every row.
+func foldRows(rows []sideRow, context int) []sideRow {
+	if context < 0 {
+		return rows
+	}
+	keep := make([]bool, len(rows))
+	for i, row := range rows {
+		if row.Kind == lineEqual {
+			continue
+		}
+		for j := max(i-context, 0); j <= min(i+context, len(rows)-1); j++ {
+			keep[j] = true
+		}
+	}
+
+	var folded []sideRow
+	for i, row := range rows {
+		if keep[i] {
+			folded = append(folded, row)
+			continue
+		}
+		if n := len(folded); n > 0 && folded[n-1].Folded > 0 {
+			folded[n-1].Folded++
+		} else {
+			folded = append(folded, sideRow{Kind: lineEqual, Folded: 1})
+		}
+	}
+	return folded
+}
+
+// wordTokens splits a line into words and the single characters between them, so intra-line
+// differences are highlighted as whole words.
+func wordTokens(line string) []string {
+	var tokens []string
+	var word strings.Builder
+	for _, r := range line {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+		tokens = append(tokens, string(r))
+	}
+	if word.Len() > 0 {
+		tokens = append(tokens, word.String())
+	}
+	return tokens
+}
+
+// wordDiff marks the words that differ between two versions of a line.
+func wordDiff(left, right string) ([]styledRune, []styledRune) {
+	var vocabulary []string
+	index := make(map[string]rune)
+	encode := func(line string) []rune {
+		var runes []rune
+		for _, token := range wordTokens(line) {
+			r, ok := index[token]
+			if !ok {
+				r = lineRune(len(vocabulary))
+				index[token] = r
+				vocabulary = append(vocabulary, token)
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(encode(left), encode(right), false)
+
+	var leftCells, rightCells []styledRune
+	add := func(cells []styledRune, text string, highlight bool) []styledRune {
+		for _, r := range text {
+			cells = append(cells, styledRune{r, highlight})
+		}
+		return cells
+	}
+	for _, d := range diffs {
+		var text strings.Builder
+		for _, r := range d.Text {
+			text.WriteString(vocabulary[runeLine(r)])
+		}
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			leftCells = add(leftCells, text.String(), false)
+			rightCells = add(rightCells, text.String(), false)
+		case diffmatchpatch.DiffDelete:
+			leftCells = add(leftCells, text.String(), true)
+		case diffmatchpatch.DiffInsert:
+			rightCells = add(rightCells, text.String(), true)
+		}
+	}
+	return leftCells, rightCells
+}
+
+func plainCells(text string) []styledRune {
+	var cells []styledRune
+	for _, r := range text {
+		cells = append(cells, styledRune{r, false})
+	}
+	return cells
+}
+
+// runeWidth approximates how many terminal columns a rune takes: none for combining marks
+// and control characters, two for East Asian wide characters and emoji.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0, unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.IsControl(r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// expandTabs replaces tabs with spaces so column widths can be computed.
+func expandTabs(cells []styledRune) []styledRune {
+	var expanded []styledRune
+	column := 0
+	for _, cell := range cells {
+		if cell.r == '\t' {
+			for n := tabWidth - column%tabWidth; n > 0; n-- {
+				expanded = append(expanded, styledRune{' ', cell.highlight})
+				column++
+			}
+			continue
+		}
+		expanded = append(expanded, cell)
+		column += runeWidth(cell.r)
+	}
+	return expanded
+}
+
+// wrapCells splits cells into lines of at most width columns, never splitting a rune. Lines
+// break after the last space when there is one in the second half of the line.
+func wrapCells(cells []styledRune, width int) [][]styledRune {
+	cells = expandTabs(cells)
+	if len(cells) == 0 {
+		return [][]styledRune{nil}
+	}
+	var lines [][]styledRune
+	var line []styledRune
+	lineWidth := 0
+	for _, cell := range cells {
+		w := runeWidth(cell.r)
+		if lineWidth+w > width && len(line) > 0 {
+			next := []styledRune(nil)
+			for i := len(line) - 1; i > len(line)/2; i-- {
+				if line[i].r == ' ' {
+					line, next = line[:i+1], append(next, line[i+1:]...)
+					break
+				}
+			}
+			lines = append(lines, line)
+			line, lineWidth = next, 0
+			for _, c := range line {
+				lineWidth += runeWidth(c.r)
+			}
+		}
+		line = append(line, cell)
+		lineWidth += w
+	}
+	return append(lines, line)
+}
+
+// renderCells colors cells and pads them to width columns.
+func renderCells(cells []styledRune, width int, base, highlight *color.Color) string {
+	var b strings.Builder
+	used := 0
+	for i := 0; i < len(cells); {
+		j := i
+		var run strings.Builder
+		for ; j < len(cells) && cells[j].highlight == cells[i].highlight; j++ {
+			run.WriteRune(cells[j].r)
+			used += runeWidth(cells[j].r)
+		}
+		style := base
+		if cells[i].highlight {
+			style = highlight
+		}
+		if style != nil {
+			b.WriteString(style.Sprint(run.String()))
+		} else {
+			b.WriteString(run.String())
+		}
+		i = j
+	}
+	if used < width {
+		b.WriteString(strings.Repeat(" ", width-used))
+	}
+	return b.String()
+}
+
+func displaySideBySideDiff(output1, output2, version1, version2 string, opts CompareOptions) {
+	width := opts.Width
+	if width <= 0 {
+		width = terminalWidth()
+	}
+	// Each side has a marker, a line number and a space; the sides are separated by " │ "
+	gutter := 1 + lineNumberWidth + 1
+	columnWidth := max((width-2*gutter-3)/2, minColumnWidth)
+
+	var (
+		blueColor      = color.New(color.FgBlue)
+		redColor       = color.New(color.FgRed)
+		greenColor     = color.New(color.FgGreen)
+		redHighlight   = color.New(color.FgRed, color.Bold, color.ReverseVideo)
+		greenHighlight = color.New(color.FgGreen, color.Bold, color.ReverseVideo)
+		dimColor       = color.New(color.Faint)
+	)
+
+	separator := strings.Repeat("─", 2*(gutter+columnWidth)+3)
+	header := func(name string) string {
+		cells := plainCells(name)
+		if len(cells) > columnWidth {
+			cells = wrapCells(cells, columnWidth-1)[0]
+			cells = append(cells, styledRune{'…', false})
+		}
+		return renderCells(cells, gutter+columnWidth, blueColor, nil)
+	}
+
+	fmt.Println(separator)
+	fmt.Printf("%s │ %s\n", header(version1), header(version2))
+	fmt.Println(separator)
+
+	for _, row := range foldRows(sideBySideRows(output1, output2), opts.Context) {
+		if row.Folded > 0 {
+			label := fmt.Sprintf("⋯ %d unchanged lines ⋯", row.Folded)
+			if row.Folded == 1 {
+				label = "⋯ 1 unchanged line ⋯"
+			}
+			padding := max((2*(gutter+columnWidth)+3-displayWidth(label))/2, 0)
+			fmt.Println(dimColor.Sprint(strings.Repeat(" ", padding) + label))
+			continue
+		}
+
+		var leftCells, rightCells []styledRune
+		var leftBase, rightBase *color.Color
+		leftMarker, rightMarker := " ", " "
+		switch row.Kind {
+		case rowChanged:
+			leftCells, rightCells = wordDiff(row.Left, row.Right)
+			leftBase, rightBase = redColor, greenColor
+			leftMarker, rightMarker = "~", "~"
+		case lineDelete:
+			leftCells = plainCells(row.Left)
+			leftBase = redColor
+			leftMarker = "-"
+		case lineInsert:
+			rightCells = plainCells(row.Right)
+			rightBase = greenColor
+			rightMarker = "+"
+		default:
+			leftCells, rightCells = plainCells(row.Left), plainCells(row.Right)
+		}
+
+		leftLines, rightLines := wrapCells(leftCells, columnWidth), wrapCells(rightCells, columnWidth)
+		if row.Kind == lineInsert {
+			leftLines = nil
+		}
+		if row.Kind == lineDelete {
+			rightLines = nil
+		}
+
+		for i := 0; i < len(leftLines) || i < len(rightLines); i++ {
+			left, right := emptyGutter(gutter), emptyGutter(gutter)
+			if i < len(leftLines) {
+				left = lineGutter(leftMarker, row.LeftNo, i == 0, leftBase)
+			}
+			if i < len(rightLines) {
+				right = lineGutter(rightMarker, row.RightNo, i == 0, rightBase)
+			}
+
+			var leftText, rightText string
+			if i < len(leftLines) {
+				leftText = renderCells(leftLines[i], columnWidth, leftBase, redHighlight)
+			} else {
+				leftText = strings.Repeat(" ", columnWidth)
+			}
+			if i < len(rightLines) {
+				rightText = renderCells(rightLines[i], columnWidth, rightBase, greenHighlight)
+			}
+			fmt.Println(strings.TrimRight(left+leftText+" │ "+right+rightText, " "))
+		}
+	}
+}
+
+func emptyGutter(width int) string {
+	return strings.Repeat(" ", width)
+}
+
+// lineGutter renders the marker and line number; continuation lines of wrapped text get
+// a wrap marker instead of the number.
+func lineGutter(marker string, number int, first bool, style *color.Color) string {
+	label := fmt.Sprintf("%*d", lineNumberWidth, number)
+	if !first {
+		label = fmt.Sprintf("%*s", lineNumberWidth, "↪")
+		marker = " "
+	}
+	text := marker + label + " "
+	if style != nil && marker != " " {
+		return style.Sprint(text)
+	}
+	return text
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestSideBySideRowsPairsChanges(t *testing.T) {
+	rows := sideBySideRows("a\nb\nc\nd", "a\nB\nc\ne\nf")
+	want := []sideRow{
+		{Kind: lineEqual, Left: "a", Right: "a", LeftNo: 1, RightNo: 1},
+		{Kind: rowChanged, Left: "b", Right: "B", LeftNo: 2, RightNo: 2},
+		{Kind: lineEqual, Left: "c", Right: "c", LeftNo: 3, RightNo: 3},
+		{Kind: rowChanged, Left: "d", Right: "e", LeftNo: 4, RightNo: 4},
+		{Kind: lineInsert, Right: "f", RightNo: 5},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %+v\nwant %+v", rows, want)
+	}
+}
+
+func TestFoldRows(t *testing.T) {
+	var left, right []string
+	for i := 1; i <= 10; i++ {
+		line := string(rune('a' + i - 1))
+		left = append(left, line)
+		if i == 5 {
+			line = "changed"
+		}
+		right = append(right, line)
+	}
+	rows := sideBySideRows(strings.Join(left, "\n"), strings.Join(right, "\n"))
+
+	folded := foldRows(rows, 1)
+	var shape []string
+	for _, row := range folded {
+		switch {
+		case row.Folded > 0:
+			shape = append(shape, strings.Repeat(".", row.Folded))
+		case row.Kind == lineEqual:
+			shape = append(shape, "=")
+		default:
+			shape = append(shape, string(row.Kind))
+		}
+	}
+	if got, want := strings.Join(shape, " "), "... = ~ = ...."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(foldRows(rows, -1)) != len(rows) {
+		t.Error("a negative context folded rows")
+	}
+}
+
+// highlighted returns the highlighted runes of cells, with a space between separate runs.
+func highlighted(cells []styledRune) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if !cell.highlight {
+			continue
+		}
+		if i > 0 && !cells[i-1].highlight && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(cell.r)
+	}
+	return b.String()
+}
+
+func TestWordDiffHighlightsWholeWords(t *testing.T) {
+	left, right := wordDiff("uploaded 10 files to repo-local", "uploaded 12 files to repo-remote")
+	if got := highlighted(left); got != "10 local" {
+		t.Errorf("left highlights %q", got)
+	}
+	if got := highlighted(right); got != "12 remote" {
+		t.Errorf("right highlights %q", got)
+	}
+}
+
+func TestWrapCells(t *testing.T) {
+	text := func(lines [][]styledRune) []string {
+		var texts []string
+		for _, line := range lines {
+			var b strings.Builder
+			for _, cell := range line {
+				b.WriteRune(cell.r)
+			}
+			texts = append(texts, b.String())
+		}
+		return texts
+	}
+	tests := []struct {
+		input string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		// Lines break after the last space in their second half
+		{"upload the files now", 12, []string{"upload the ", "files now"}},
+		// Without such a space, long words are cut
+		{"abcdefghijkl", 5, []string{"abcde", "fghij", "kl"}},
+		// Wide runes take two columns and are never split
+		{"日本語の文", 5, []string{"日本", "語の", "文"}},
+		{"\tx", 10, []string{"    x"}},
+	}
+	for _, test := range tests {
+		got := text(wrapCells(plainCells(test.input), test.width))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q at %d: got %q, want %q", test.input, test.width, got, test.want)
+		}
+		for _, line := range got {
+			if displayWidth(line) > test.width {
+				t.Errorf("%q at %d: %q is wider than the column", test.input, test.width, line)
+			}
+		}
+	}
+}
+
+func TestDisplaySideBySideDiffFitsWidth(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	long := strings.Repeat("word ", 20)
+	output := captureStdout(t, func() {
+		displaySideBySideDiff("same\n"+long+"old", "same\n"+long+"new\nadded", "2.70.0", "2.71.0", CompareOptions{Width: 60, Context: -1})
+	})
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for _, line := range lines {
+		if width := displayWidth(line); width > 60 {
+			t.Errorf("line is %d columns wide: %q", width, line)
+		}
+	}
+	for _, want := range []string{
+		"   1 same",
+		"~   2 word word",
+		"     ↪ word",
+		"+   3 added",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output lacks %q:\n%s", want, output)
+		}
+	}
+}
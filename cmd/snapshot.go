@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+// snapshotNamePattern allows a name with an optional group, e.g. rt-ping or upgrade-checks/ping.
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)?$`)
+
+// defaultSnapshotTimeout applies to snapshots recorded without a timeout.
+const defaultSnapshotTimeout = 30 * time.Second
+
+// GoldenSnapshot is the recorded result of a command, which later versions are verified against.
+// It keeps everything needed to re-run the command and compare the same way.
+type GoldenSnapshot struct {
+	Name             string                   `json:"name"`
+	Version          string                   `json:"version"`
+	Args             []string                 `json:"args"`
+	Env              map[string]string        `json:"env,omitempty"`
+	Stdin            string                   `json:"stdin,omitempty"`
+	Timeout          time.Duration            `json:"timeout_ns"`
+	Rules            utils.NormalizationRules `json:"normalization"`
+	Channels         []string                 `json:"channels"`
+	IgnoreArrayOrder bool                     `json:"ignore_array_order,omitempty"`
+	IgnorePaths      []string                 `json:"ignore_paths,omitempty"`
+	Result           ExecutionResult          `json:"result"`
+	RecordedAt       time.Time                `json:"recorded_at"`
+}
+
+var snapshotFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "normalize",
+		Usage: "Built-in normalizers to apply: timestamps, uuids, ids, paths, durations or all",
+	},
+	&cli.StringSliceFlag{
+		Name:  "replace",
+		Usage: "Regex replace rule, as <regex>=><replacement> (can be repeated)",
+	},
+	&cli.StringSliceFlag{
+		Name:  "ignore-lines",
+		Usage: "Drop output lines matching this regex (can be repeated)",
+	},
+	&cli.StringFlag{
+		Name:  "profile",
+		Usage: "Apply a saved normalization profile",
+	},
+	&cli.StringSliceFlag{
+		Name:  "channels",
+		Usage: "Result channels that count as differences: stdout, stderr, exit",
+		Value: cli.NewStringSlice(defaultChannels...),
+	},
+	&cli.BoolFlag{
+		Name:  "ignore-array-order",
+		Usage: "Treat JSON arrays as unordered when verifying",
+	},
+	&cli.StringSliceFlag{
+		Name:  "ignore-path",
+		Usage: "JSON path to leave out when verifying (can be repeated)",
+	},
+	&cli.IntFlag{
+		Name:  "timeout",
+		Usage: "Command timeout in seconds",
+		Value: 30,
+	},
+}
+
+var Snapshot = &cli.Command{
+	Name:        "snapshot",
+	Usage:       descriptions.Snapshot.Usage,
+	Description: descriptions.Snapshot.Format(),
+	Subcommands: []*cli.Command{
+		{
+			Name:      "record",
+			Usage:     "Record the result of a command, or of every case of a suite",
+			ArgsUsage: "<version> -- <jf-command> [args...] | --suite <file> <version>",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "Snapshot name (default: derived from the command)",
+				},
+				&cli.StringFlag{
+					Name:  "suite",
+					Usage: "Record every case of a compare suite, as <suite-name>/<case-name>",
+				},
+			}, snapshotFlags...),
+			Action: func(c *cli.Context) error {
+				return recordSnapshots(c)
+			},
+		},
+		{
+			Name:      "verify",
+			Usage:     "Re-run snapshotted commands with a version and diff the results",
+			ArgsUsage: "<version> [name or group...]",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "update",
+					Usage: "Accept the new results as the snapshots",
+				},
+				&cli.IntFlag{
+					Name:  "parallel",
+					Usage: "Snapshots to verify at the same time",
+					Value: defaultSuiteParallel,
+				},
+				&cli.BoolFlag{
+					Name:  "unified",
+					Usage: "Show unified diff format instead of side-by-side",
+				},
+				&cli.IntFlag{
+					Name:  "context",
+					Usage: "Unchanged lines to show around each change; -1 shows the full output",
+					Value: 3,
+				},
+				&cli.IntFlag{
+					Name:  "width",
+					Usage: "Side-by-side diff width in columns (default: terminal width or $COLUMNS)",
+				},
+				&cli.BoolFlag{
+					Name:  "no-color",
+					Usage: "Disable colored output",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return verifySnapshots(c)
+			},
+		},
+		{
+			Name:      "list",
+			Usage:     "List recorded snapshots",
+			ArgsUsage: "[name or group...]",
+			Action: func(c *cli.Context) error {
+				snapshots, err := loadSnapshots(c.Args().Slice())
+				if err != nil {
+					return err
+				}
+				if len(snapshots) == 0 {
+					fmt.Println("📭 No snapshots recorded. Record one with: jfvm snapshot record <version> -- <jf-command>")
+					return nil
+				}
+				width := 0
+				for _, snapshot := range snapshots {
+					width = max(width, len(snapshot.Name))
+				}
+				for _, snapshot := range snapshots {
+					fmt.Printf("📸 %-*s  %-10s exit %-3d jf %s (%s)\n", width, snapshot.Name, snapshot.Version, snapshot.Result.ExitCode, strings.Join(snapshot.Args, " "), snapshot.RecordedAt.Local().Format("2006-01-02 15:04"))
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "delete",
+			Usage:     "Delete snapshots",
+			ArgsUsage: "<name or group...>",
+			Action: func(c *cli.Context) error {
+				if c.NArg() == 0 {
+					return cli.Exit("Usage: jfvm snapshot delete <name or group...>", 1)
+				}
+				snapshots, err := loadSnapshots(c.Args().Slice())
+				if err != nil {
+					return err
+				}
+				if len(snapshots) == 0 {
+					return cli.Exit(fmt.Sprintf("No snapshots match %s", strings.Join(c.Args().Slice(), ", ")), 1)
+				}
+				for _, snapshot := range snapshots {
+					if err := os.Remove(snapshotPath(snapshot.Name)); err != nil {
+						return fmt.Errorf("failed to delete snapshot %s: %w", snapshot.Name, err)
+					}
+					// Drop the group directory once it's empty
+					_ = os.Remove(filepath.Dir(snapshotPath(snapshot.Name)))
+					fmt.Printf("🗑️  Deleted snapshot %s\n", snapshot.Name)
+				}
+				return nil
+			},
+		},
+	},
+}
+
+func snapshotPath(name string) string {
+	return filepath.Join(utils.JfvmSnapshots, filepath.FromSlash(name)+".json")
+}
+
+// snapshotName derives a name from a command, e.g. "rt ping" becomes rt-ping.
+func snapshotName(args []string) string {
+	name := strings.Trim(unsafeNameChars.ReplaceAllString(strings.Join(args, "-"), "-"), "-.")
+	if len(name) > 64 {
+		name = strings.TrimRight(name[:64], "-.")
+	}
+	if name == "" {
+		name = "snapshot"
+	}
+	return name
+}
+
+func saveSnapshot(snapshot GoldenSnapshot) error {
+	path := snapshotPath(snapshot.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadSnapshots loads the snapshots matching the filters, sorted by name. A filter matches
+// a snapshot by name or by group; no filters match every snapshot.
+func loadSnapshots(filters []string) ([]GoldenSnapshot, error) {
+	var snapshots []GoldenSnapshot
+	err := filepath.WalkDir(utils.JfvmSnapshots, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var snapshot GoldenSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return fmt.Errorf("invalid snapshot %s: %w", path, err)
+		}
+
+		if len(filters) > 0 && !slices.ContainsFunc(filters, func(filter string) bool {
+			filter = strings.TrimSuffix(filter, "/")
+			return snapshot.Name == filter || strings.HasPrefix(snapshot.Name, filter+"/")
+		}) {
+			return nil
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots, nil
+}
+
+// runSnapshot runs a snapshot's command with version and normalizes the result the way the
+// snapshot was recorded.
+func runSnapshot(snapshot GoldenSnapshot, version string) (ExecutionResult, error) {
+	normalizer, err := snapshot.Rules.Compile()
+	if err != nil {
+		return ExecutionResult{}, fmt.Errorf("snapshot %s: %w", snapshot.Name, err)
+	}
+	timeout := snapshot.Timeout
+	if timeout <= 0 {
+		timeout = defaultSnapshotTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, err := executeJFCommand(ctx, version, snapshot.Args, RunOptions{Env: expandEnv(snapshot.Env), Stdin: snapshot.Stdin})
+	if err != nil {
+		return result, err
+	}
+	normalizeResult(&result, normalizer)
+	return result, nil
+}
+
+// snapshotChannels rejects the files and network channels: snapshots run without a scratch
+// directory or the recording proxy, so those channels would always verify.
+func snapshotChannels(channels []string) error {
+	for _, channel := range []string{ChannelFiles, ChannelNetwork} {
+		if slices.Contains(channels, channel) {
+			return fmt.Errorf("snapshots don't record the %s channel", channel)
+		}
+	}
+	return nil
+}
+
+// snapshotTemplates returns the snapshots to record, without results.
+func snapshotTemplates(c *cli.Context, version string, args []string) ([]GoldenSnapshot, error) {
+	rules, err := compareNormalizationRules(c)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := parseChannels(c.StringSlice("channels"))
+	if err != nil {
+		return nil, err
+	}
+	if err := snapshotChannels(channels); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(c.Int("timeout")) * time.Second
+
+	if path := c.String("suite"); path != "" {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("a suite is recorded from its file; don't pass a command")
+		}
+		suite, err := loadCompareSuite(path)
+		if err != nil {
+			return nil, err
+		}
+		base := CompareOptions{Channels: channels, JSON: JSONDiffOptions{IgnoreArrayOrder: c.Bool("ignore-array-order"), IgnorePaths: c.StringSlice("ignore-path")}}
+		group := strings.Trim(unsafeNameChars.ReplaceAllString(suite.Name, "-"), "-.")
+		var snapshots []GoldenSnapshot
+		for _, suiteCase := range suite.Cases {
+			run, err := suite.prepare(suiteCase, rules, base, timeout)
+			if err != nil {
+				return nil, err
+			}
+			if err := snapshotChannels(run.Opts.channels()); err != nil {
+				return nil, fmt.Errorf("case '%s': %w", suiteCase.Name, err)
+			}
+			snapshots = append(snapshots, GoldenSnapshot{
+				Name:             group + "/" + snapshotName([]string{run.Name}),
+				Version:          version,
+				Args:             run.Command,
+				Env:              run.Env,
+				Stdin:            run.Run.Stdin,
+				Timeout:          run.Timeout,
+				Rules:            run.Rules,
+				Channels:         run.Opts.channels(),
+				IgnoreArrayOrder: run.Opts.JSON.IgnoreArrayOrder,
+				IgnorePaths:      run.Opts.JSON.IgnorePaths,
+			})
+		}
+		return snapshots, nil
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no JFrog CLI command specified after '--'")
+	}
+	name := c.String("name")
+	if name == "" {
+		name = snapshotName(args)
+	}
+	if _, err := rules.Compile(); err != nil {
+		return nil, err
+	}
+	return []GoldenSnapshot{{
+		Name:             name,
+		Version:          version,
+		Args:             args,
+		Timeout:          timeout,
+		Rules:            rules,
+		Channels:         channels,
+		IgnoreArrayOrder: c.Bool("ignore-array-order"),
+		IgnorePaths:      c.StringSlice("ignore-path"),
+	}}, nil
+}
+
+func recordSnapshots(c *cli.Context) error {
+	args := c.Args().Slice()
+	usage := "Usage: jfvm snapshot record <version> -- <jf-command> [args...] or jfvm snapshot record --suite <file> <version>"
+	if err := misplacedFlagError(c); err != nil {
+		return err
+	}
+	if len(args) == 0 || args[0] == "--" {
+		return cli.Exit(usage, 1)
+	}
+	version, err := utils.ResolveVersionOrAlias(args[0])
+	if err != nil {
+		version = args[0]
+	}
+	if err := utils.CheckVersionExists(version); err != nil {
+		return cli.Exit(fmt.Sprintf("Version %s not found: %v", args[0], err), 1)
+	}
+
+	rest := args[1:]
+	if len(rest) > 0 && rest[0] == "--" {
+		rest = rest[1:]
+	} else if len(rest) > 0 {
+		return cli.Exit("Missing '--' separator. "+usage, 1)
+	}
+
+	snapshots, err := snapshotTemplates(c, version, rest)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	for _, snapshot := range snapshots {
+		if !snapshotNamePattern.MatchString(snapshot.Name) {
+			return cli.Exit(fmt.Sprintf("Invalid snapshot name '%s': use letters, digits, '.', '_' and '-', with an optional group/", snapshot.Name), 1)
+		}
+	}
+
+	for _, snapshot := range snapshots {
+		result, err := runSnapshot(snapshot, version)
+		if err != nil {
+			return err
+		}
+		snapshot.Result = result
+		snapshot.RecordedAt = time.Now()
+
+		verb := "Recorded"
+		if _, err := os.Stat(snapshotPath(snapshot.Name)); err == nil {
+			verb = "Re-recorded"
+		}
+		if err := saveSnapshot(snapshot); err != nil {
+			return fmt.Errorf("failed to save snapshot %s: %w", snapshot.Name, err)
+		}
+		fmt.Printf("📸 %s %s from %s: jf %s (exit %d)\n", verb, snapshot.Name, version, strings.Join(snapshot.Args, " "), result.ExitCode)
+		if result.ErrorMsg != "" {
+			fmt.Printf("⚠️  %s: %s\n", snapshot.Name, result.ErrorMsg)
+		}
+	}
+	return nil
+}
+
+// snapshotCheck is the outcome of verifying one snapshot.
+type snapshotCheck struct {
+	snapshot   GoldenSnapshot
+	result     ExecutionResult
+	comparison Comparison
+	report     CompareReport
+	opts       CompareOptions
+	err        error
+}
+
+func verifySnapshots(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return cli.Exit("Usage: jfvm snapshot verify <version> [name or group...]", 1)
+	}
+	if c.Bool("no-color") {
+		color.NoColor = true
+	}
+	version, err := utils.ResolveVersionOrAlias(c.Args().First())
+	if err != nil {
+		version = c.Args().First()
+	}
+	if err := utils.CheckVersionExists(version); err != nil {
+		return cli.Exit(fmt.Sprintf("Version %s not found: %v", c.Args().First(), err), 1)
+	}
+
+	snapshots, err := loadSnapshots(c.Args().Tail())
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return cli.Exit("No snapshots to verify. Record one with: jfvm snapshot record <version> -- <jf-command>", 1)
+	}
+
+	fmt.Printf("🔍 Verifying %d snapshots with %s\n\n", len(snapshots), version)
+
+	checks := make([]snapshotCheck, len(snapshots))
+	g, _ := errgroup.WithContext(context.Background())
+	g.SetLimit(max(c.Int("parallel"), 1))
+	for i, snapshot := range snapshots {
+		g.Go(func() error {
+			checks[i] = verifySnapshot(c, snapshot, version)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var (
+		redColor    = color.New(color.FgRed)
+		greenColor  = color.New(color.FgGreen)
+		yellowColor = color.New(color.FgYellow)
+	)
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.snapshot.Name))
+	}
+
+	mismatched, updated := 0, 0
+	for _, check := range checks {
+		switch {
+		case check.err != nil:
+			mismatched++
+			fmt.Printf("%s %-*s  %v\n", redColor.Sprint("❌"), width, check.snapshot.Name, check.err)
+		case check.report.Identical:
+			fmt.Printf("%s %-*s  matches %s\n", greenColor.Sprint("✅"), width, check.snapshot.Name, check.snapshot.Version)
+		case c.Bool("update"):
+			updated++
+			fmt.Printf("%s %-*s  updated (%s)\n", yellowColor.Sprint("📸"), width, check.snapshot.Name, check.report.Diffs[0].Summary)
+		default:
+			mismatched++
+			fmt.Printf("%s %-*s  %s\n", redColor.Sprint("❌"), width, check.snapshot.Name, check.report.Diffs[0].Summary)
+		}
+	}
+	fmt.Println()
+
+	for _, check := range checks {
+		if check.err != nil || check.report.Identical {
+			continue
+		}
+		fmt.Printf("═══ %s: jf %s ═══\n", check.snapshot.Name, strings.Join(check.snapshot.Args, " "))
+		displayComparison(check.comparison, check.opts)
+		fmt.Println()
+
+		if c.Bool("update") {
+			snapshot := check.snapshot
+			snapshot.Version = version
+			snapshot.Result = check.result
+			snapshot.RecordedAt = time.Now()
+			if err := saveSnapshot(snapshot); err != nil {
+				return fmt.Errorf("failed to update snapshot %s: %w", snapshot.Name, err)
+			}
+		}
+	}
+
+	if updated > 0 {
+		fmt.Printf("📸 Updated %d snapshots from %s\n", updated, version)
+	}
+	if mismatched > 0 {
+		return cli.Exit(fmt.Sprintf("❌ %d of %d snapshots don't match %s; accept the changes with --update", mismatched, len(snapshots), version), 1)
+	}
+	fmt.Printf("✅ %s matches %d snapshots\n", version, len(snapshots)-updated)
+	return nil
+}
+
+// verifySnapshot re-runs a snapshot with version and compares the result against it.
+func verifySnapshot(c *cli.Context, snapshot GoldenSnapshot, version string) snapshotCheck {
+	check := snapshotCheck{snapshot: snapshot}
+	result, err := runSnapshot(snapshot, version)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	check.result = result
+
+	check.opts = CompareOptions{
+		Unified:  c.Bool("unified"),
+		NoColor:  c.Bool("no-color"),
+		Context:  c.Int("context"),
+		Width:    c.Int("width"),
+		Channels: snapshot.Channels,
+		JSON:     JSONDiffOptions{IgnoreArrayOrder: snapshot.IgnoreArrayOrder, IgnorePaths: snapshot.IgnorePaths},
+	}
+	// The snapshot may come from the version being verified, so it gets its own name
+	recorded := snapshot.Result
+	recorded.Version = fmt.Sprintf("snapshot (%s)", snapshot.Version)
+	check.comparison = groupResults(strings.Join(snapshot.Args, " "), recorded.Version, []ExecutionResult{recorded, result}, check.opts)
+	check.report = buildCompareReport(check.comparison, check.opts)
+	return check
+}
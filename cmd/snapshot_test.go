@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestSnapshotRecordSuiteExample(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installTestVersion(t, "2.70.0")
+	suite := "name: upgrade-checks\ncases:\n  - name: ping\n    args: [rt, ping]\n  - name: version\n    args: [--version]\n"
+	if err := os.WriteFile("checks.yaml", []byte(suite), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	example := documentedExample(t, descriptions.Snapshot, "--suite")
+	captureStdout(t, func() {
+		if err := runCommandLine(t, example); err != nil {
+			t.Errorf("%s: %v", example, err)
+		}
+	})
+
+	for _, name := range []string{"ping", "version"} {
+		if _, err := os.Stat(filepath.Join(utils.JfvmSnapshots, "upgrade-checks", name+".json")); err != nil {
+			t.Errorf("no snapshot recorded for case %s", name)
+		}
+	}
+}
+
+func TestSnapshotRecordRejectsUnrecordedChannels(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installTestVersion(t, "2.70.0")
+	suite := "name: tree\ncases:\n  - name: upload\n    args: [rt, upload]\n    channels: [stdout, files]\n"
+	if err := os.WriteFile("tree.yaml", []byte(suite), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for line, want := range map[string]string{
+		"jfvm snapshot record --suite tree.yaml 2.70.0":                    "case 'upload': snapshots don't record the files channel",
+		"jfvm snapshot record --channels stdout,network 2.70.0 -- rt ping": "snapshots don't record the network channel",
+	} {
+		if err := runCommandLine(t, line); err == nil || err.Error() != want {
+			t.Errorf("%s: got %v, want %q", line, err, want)
+		}
+	}
+	if _, err := os.Stat(utils.JfvmSnapshots); err == nil {
+		t.Error("snapshots were recorded")
+	}
+}
+
+func TestRunSnapshotWithoutTimeoutUsesDefault(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", "echo pong")
+
+	// Snapshots written by hand or by older versions may have no timeout_ns
+	result, err := runSnapshot(GoldenSnapshot{Name: "ping", Args: []string{"rt", "ping"}}, "2.70.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || result.Output != "pong\n" {
+		t.Errorf("got exit %d and output %q, want pong", result.ExitCode, result.Output)
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	for args, want := range map[string]string{
+		"rt ping":                      "rt-ping",
+		"rt search libs/*.jar --count": "rt-search-libs-.jar---count",
+		"":                             "snapshot",
+		strings.Repeat("a ", 40):       strings.Repeat("a-", 31) + "a",
+	} {
+		if got := snapshotName(strings.Fields(args)); got != want {
+			t.Errorf("%q: got %q, want %q", args, got, want)
+		}
+	}
+}
+
+func TestSnapshotVerifyAndUpdate(t *testing.T) {
+	useTestRoot(t)
+	installTestScript(t, "2.70.0", "echo pong at 2025-03-01T10:00:00Z")
+	installTestScript(t, "2.71.0", "echo pong at 2025-04-02T11:30:00Z")
+	installTestScript(t, "2.72.0", "echo PONG at 2025-04-02T11:30:00Z")
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm snapshot record --normalize timestamps --name ping 2.70.0 -- rt ping"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	snapshots, err := loadSnapshots(nil)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("got %d snapshots (%v)", len(snapshots), err)
+	}
+	if snapshot := snapshots[0]; snapshot.Version != "2.70.0" || snapshot.Result.Output != "pong at <TIMESTAMP>\n" || snapshot.Timeout != defaultSnapshotTimeout {
+		t.Errorf("snapshot is %+v", snapshot)
+	}
+
+	// The timestamp differs, but the snapshot normalizes it the way it was recorded
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm snapshot verify --no-color 2.71.0"); err != nil {
+			t.Error(err)
+		}
+	})
+	if !strings.Contains(stdout, "ping  matches 2.70.0") {
+		t.Errorf("unexpected verify output:\n%s", stdout)
+	}
+
+	captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm snapshot verify --no-color 2.72.0")
+	})
+	if err == nil || err.Error() != "❌ 1 of 1 snapshots don't match 2.72.0; accept the changes with --update" {
+		t.Errorf("got %v", err)
+	}
+
+	stdout = captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm snapshot verify --no-color --update 2.72.0"); err != nil {
+			t.Error(err)
+		}
+	})
+	if !strings.Contains(stdout, "📸 Updated 1 snapshots from 2.72.0") {
+		t.Errorf("unexpected update output:\n%s", stdout)
+	}
+	if snapshots, _ := loadSnapshots([]string{"ping"}); len(snapshots) != 1 || snapshots[0].Version != "2.72.0" || snapshots[0].Result.Output != "PONG at <TIMESTAMP>\n" {
+		t.Errorf("snapshot after --update is %+v", snapshots)
+	}
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm snapshot verify --no-color 2.72.0"); err != nil {
+			t.Errorf("the updated snapshot doesn't match: %v", err)
+		}
+	})
+}
+
+func TestSnapshotListAndDeleteByGroup(t *testing.T) {
+	useTestRoot(t)
+	installTestVersion(t, "2.70.0")
+	captureStdout(t, func() {
+		for _, line := range []string{
+			"jfvm snapshot record --name smoke/ping 2.70.0 -- rt ping",
+			"jfvm snapshot record --name smoke/version 2.70.0 -- --version",
+			"jfvm snapshot record 2.70.0 -- rt ping",
+		} {
+			if err := runCommandLine(t, line); err != nil {
+				t.Fatalf("%s: %v", line, err)
+			}
+		}
+	})
+
+	names := func(filters ...string) []string {
+		snapshots, err := loadSnapshots(filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, snapshot := range snapshots {
+			names = append(names, snapshot.Name)
+		}
+		return names
+	}
+	if got := strings.Join(names(), " "); got != "rt-ping smoke/ping smoke/version" {
+		t.Errorf("all snapshots: %s", got)
+	}
+	if got := strings.Join(names("smoke/"), " "); got != "smoke/ping smoke/version" {
+		t.Errorf("group smoke: %s", got)
+	}
+	if got := strings.Join(names("smoke/ping", "rt-ping"), " "); got != "rt-ping smoke/ping" {
+		t.Errorf("by name: %s", got)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm snapshot delete smoke"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := strings.Join(names(), " "); got != "rt-ping" {
+		t.Errorf("after deleting the group: %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(utils.JfvmSnapshots, "smoke")); !os.IsNotExist(err) {
+		t.Error("the empty group directory was left behind")
+	}
+	if err := runCommandLine(t, "jfvm snapshot delete smoke"); err == nil || err.Error() != "No snapshots match smoke" {
+		t.Errorf("got %v", err)
+	}
+	if err := runCommandLine(t, "jfvm snapshot record --name ../escape 2.70.0 -- rt ping"); err == nil || !strings.HasPrefix(err.Error(), "Invalid snapshot name '../escape'") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// parseSourceSpec splits <git-url-or-path>@<ref>. The ref separator is only looked for in
+// the path of the source, so the user part of ssh://git@host:2222/repo.git and of scp-like
+// URLs such as git@github.com:jfrog/jfrog-cli.git is never mistaken for a ref.
+func parseSourceSpec(spec string) (string, string, error) {
+	source, ref := spec, ""
+	start := sourcePathStart(spec)
+	// Git ref names cannot contain ':', so an '@' followed by one is not a ref separator
+	if i := strings.LastIndex(spec[start:], "@"); i >= 0 && start+i > 0 && !strings.Contains(spec[start+i+1:], ":") {
+		source, ref = spec[:start+i], spec[start+i+1:]
+	}
+	if source == "" {
+		return "", "", fmt.Errorf("invalid source '%s': expected <git-url-or-path>@<ref>", spec)
+	}
+	if ref == "" {
+		return "", "", fmt.Errorf("no ref in '%s': expected <git-url-or-path>@<ref>, e.g. https://github.com/jfrog/jfrog-cli.git@master", spec)
+	}
+
+	// Local checkouts are mirrored by absolute path, so the same directory always maps to one mirror
+	if _, err := os.Stat(source); err == nil {
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return "", "", err
+		}
+		source = abs
+	}
+	return source, ref, nil
+}
+
+// sourcePathStart returns the offset at which the repository path of a source begins:
+// after the host of a URL, after the colon of an scp-like URL, or 0 for a local path.
+func sourcePathStart(spec string) int {
+	if i := strings.Index(spec, "://"); i >= 0 {
+		if j := strings.Index(spec[i+3:], "/"); j >= 0 {
+			return i + 3 + j
+		}
+		return len(spec)
+	}
+	if colon := strings.Index(spec, ":"); colon >= 0 && !strings.Contains(spec[:colon], "/") {
+		return colon + 1
+	}
+	return 0
+}
+
+// sourceVersionName is the version name a build of ref is installed under, e.g. src-feature-x.
+func sourceVersionName(ref string) string {
+	return "src-" + strings.Trim(unsafeNameChars.ReplaceAllString(ref, "-"), "-")
+}
+
+// sourceMirror keeps a bare mirror of source under ~/.jfvm/sources and fetches it, so
+// repeated builds only transfer new commits and local checkouts are never touched.
+func sourceMirror(source string) (string, error) {
+	sum := sha256.Sum256([]byte(source))
+	name := strings.TrimSuffix(filepath.Base(strings.TrimRight(source, "/")), ".git")
+	mirror := filepath.Join(utils.JfvmSources, fmt.Sprintf("%s-%s.git", unsafeNameChars.ReplaceAllString(name, "-"), hex.EncodeToString(sum[:])[:12]))
+
+	if _, err := os.Stat(mirror); os.IsNotExist(err) {
+		if err := os.MkdirAll(utils.JfvmSources, 0755); err != nil {
+			return "", err
+		}
+		fmt.Printf("📥 Cloning %s...\n", source)
+		if err := runGit("", "clone", "--mirror", "--quiet", source, mirror); err != nil {
+			_ = os.RemoveAll(mirror)
+			return "", fmt.Errorf("failed to clone %s: %w", source, err)
+		}
+		return mirror, nil
+	}
+
+	fmt.Printf("🔄 Fetching %s...\n", source)
+	if err := runGit(mirror, "remote", "update", "--prune"); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	return mirror, nil
+}
+
+func runGit(dir string, args ...string) error {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// installFromSource builds jf from a git source at ref and installs it as a version.
+func installFromSource(spec, name string, force bool) error {
+	for _, tool := range []string{"git", "go"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("installing from source needs %s on PATH", tool)
+		}
+	}
+
+	source, ref, err := parseSourceSpec(spec)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = sourceVersionName(ref)
+	}
+	if name == "src-" || unsafeNameChars.MatchString(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid version name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	if utils.IsReleaseVersion(name) {
+		return fmt.Errorf("'%s' looks like a release version; choose a name that can't be confused with one", name)
+	}
+
+	mirror, err := sourceMirror(source)
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command("git", "-C", mirror, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return fmt.Errorf("ref '%s' not found in %s", ref, source)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	if meta, _ := utils.LoadVersionMetadata(name); meta != nil && meta.GitCommit == commit && !force {
+		if utils.CheckVersionExists(name) == nil {
+			fmt.Printf("✅ %s is already built from %s (%s)\n", name, ref, commit[:12])
+			return nil
+		}
+	}
+
+	worktree, err := os.MkdirTemp("", "jfvm-src-")
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(worktree)
+	if err := runGit(mirror, "worktree", "add", "--detach", "--quiet", worktree, commit); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+	defer func() {
+		_ = runGit(mirror, "worktree", "remove", "--force", worktree)
+		_ = os.RemoveAll(worktree)
+	}()
+
+	fmt.Printf("🔨 Building %s at %s (%s)...\n", source, ref, commit[:12])
+	cliVersion, err := goBuildJf(worktree, name)
+	if err != nil {
+		return err
+	}
+	if linked, err := utils.DedupBinaries(name, false); err == nil && len(linked) > 0 {
+		fmt.Printf("🔗 %s is identical to %s, stored once\n", name, linked[0].LinkedTo)
+	}
+
+	if err := utils.SaveVersionMetadata(name, utils.VersionMetadata{
+		Source:     utils.SourceGit,
+		Repo:       source,
+		GitRef:     ref,
+		GitCommit:  commit,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Installed %s (jf %s) from %s@%s\n", name, cliVersion, source, ref)
+	return nil
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestParseSourceSpec(t *testing.T) {
+	tests := []struct {
+		spec, source, ref string
+	}{
+		{"https://github.com/jfrog/jfrog-cli.git@master", "https://github.com/jfrog/jfrog-cli.git", "master"},
+		{"https://github.com/jfrog/jfrog-cli.git@feature/x", "https://github.com/jfrog/jfrog-cli.git", "feature/x"},
+		{"ssh://git@example.com:2222/jfrog/jfrog-cli.git@v2.74.0", "ssh://git@example.com:2222/jfrog/jfrog-cli.git", "v2.74.0"},
+		{"git@github.com:jfrog/jfrog-cli.git@main", "git@github.com:jfrog/jfrog-cli.git", "main"},
+		{"no-such-dir/jfrog-cli@fix-123", "no-such-dir/jfrog-cli", "fix-123"},
+	}
+	for _, test := range tests {
+		source, ref, err := parseSourceSpec(test.spec)
+		if err != nil {
+			t.Errorf("%s: %v", test.spec, err)
+			continue
+		}
+		if source != test.source || ref != test.ref {
+			t.Errorf("%s: got %q @ %q, want %q @ %q", test.spec, source, ref, test.source, test.ref)
+		}
+	}
+}
+
+func TestParseSourceSpecWithoutRef(t *testing.T) {
+	for _, spec := range []string{
+		"ssh://example.com:2222/jfrog/jfrog-cli.git",
+		"ssh://git@example.com:2222/jfrog/jfrog-cli.git",
+		"ssh://git@example.com/jfrog/jfrog-cli.git",
+		"git@github.com:jfrog/jfrog-cli.git",
+		"https://github.com/jfrog/jfrog-cli.git@",
+		"@master",
+	} {
+		if source, ref, err := parseSourceSpec(spec); err == nil {
+			t.Errorf("%s: got %q @ %q, want an error", spec, source, ref)
+		}
+	}
+}
+
+func TestSourceVersionName(t *testing.T) {
+	for ref, want := range map[string]string{
+		"master":          "src-master",
+		"feature/x":       "src-feature-x",
+		"v2.74.0":         "src-v2.74.0",
+		"refs/heads/fix!": "src-refs-heads-fix",
+	} {
+		if got := sourceVersionName(ref); got != want {
+			t.Errorf("%s: got %s, want %s", ref, got, want)
+		}
+	}
+}
+
+// gitSourceRepo creates a git repository holding a main package that reports version.
+func gitSourceRepo(t *testing.T, version string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	repo := t.TempDir()
+	git(t, repo, "init", "--quiet", "--initial-branch=main")
+	commitSourceVersion(t, repo, version)
+	return repo
+}
+
+// commitSourceVersion commits a main package to repo that prints "jf version <version>".
+func commitSourceVersion(t *testing.T, repo, version string) {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":  "module example.com/jf\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"jf version " + version + "\") }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, repo, "add", "-A")
+	git(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", version)
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestInstallFromSource(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a Go program")
+	}
+	useTestRoot(t)
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "off")
+	repo := gitSourceRepo(t, "2.75.0-dev")
+	git(t, repo, "tag", "v2.75.0-dev")
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm install --from-source "+repo+"@v2.75.0-dev"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	meta, err := utils.LoadVersionMetadata("src-v2.75.0-dev")
+	if err != nil || meta == nil {
+		t.Fatalf("no metadata for src-v2.75.0-dev: %v", err)
+	}
+	if meta.Source != utils.SourceGit || meta.Repo != repo || meta.GitRef != "v2.75.0-dev" ||
+		meta.GitCommit != git(t, repo, "rev-parse", "HEAD") || meta.CLIVersion != "2.75.0-dev" {
+		t.Errorf("metadata is %+v", meta)
+	}
+	if entries, _ := os.ReadDir(utils.JfvmSources); len(entries) != 1 {
+		t.Errorf("sources hold %d mirrors, want 1", len(entries))
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm install --from-source "+repo+"@v2.75.0-dev"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "is already built from v2.75.0-dev") {
+		t.Errorf("an unchanged ref was rebuilt:\n%s", stdout)
+	}
+
+	// A branch that moved is fetched into the existing mirror and rebuilt
+	commitSourceVersion(t, repo, "2.76.0-dev")
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm install --from-source "+repo+"@main --name dev"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if meta, _ := utils.LoadVersionMetadata("dev"); meta == nil || meta.CLIVersion != "2.76.0-dev" {
+		t.Errorf("dev metadata is %+v, want a build of 2.76.0-dev", meta)
+	}
+	if entries, _ := os.ReadDir(utils.JfvmSources); len(entries) != 1 {
+		t.Errorf("sources hold %d mirrors, want the one mirror fetched again", len(entries))
+	}
+}
+
+func TestInstallFromSourceRejectsBadNamesAndRefs(t *testing.T) {
+	useTestRoot(t)
+	repo := gitSourceRepo(t, "2.75.0-dev")
+	for line, want := range map[string]string{
+		"jfvm install --from-source " + repo + "@main --name 2.75.0":  "'2.75.0' looks like a release version; choose a name that can't be confused with one",
+		"jfvm install --from-source " + repo + "@main --name .hidden": "invalid version name '.hidden': use letters, digits, '.', '_' and '-'",
+		"jfvm install --from-source " + repo + "@no-such-branch":      "ref 'no-such-branch' not found in " + repo,
+		"jfvm install --from-source " + repo + "@main 2.75.0":         "--from-source cannot be combined with a version argument; use --name to choose the version name",
+	} {
+		var err error
+		captureStdout(t, func() { err = runCommandLine(t, line) })
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: got %v, want %q", line, err, want)
+		}
+	}
+	if installed, _ := utils.ListInstalledVersions(); len(installed) > 0 {
+		t.Errorf("versions were created: %v", installed)
+	}
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
+	"github.com/urfave/cli/v2"
+)
+
+var Sync = &cli.Command{
+	Name:        "sync",
+	Usage:       descriptions.Sync.Usage,
+	Description: descriptions.Sync.Format(),
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "no-use",
+			Usage: "Install everything but don't switch the active version",
+			Value: false,
+		},
+		&cli.BoolFlag{
+			Name:  "skip-plugins",
+			Usage: "Don't install the plugins the manifest requires",
+			Value: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		manifest, err := utils.FindProjectManifest()
+		if err != nil {
+			return err
+		}
+		if manifest == nil {
+			return cli.Exit(fmt.Sprintf("No %s found in this directory or its parents", utils.ManifestFile), 1)
+		}
+		fmt.Printf("📄 Syncing with %s\n", manifest.Path)
+		utils.RecordProjectFile(manifest.Path)
+
+		required := manifest.Version
+		if required == "" {
+			// Fall back to a .jfrog-version file next to the manifest
+			if data, err := os.ReadFile(filepath.Join(filepath.Dir(manifest.Path), utils.ProjectFile)); err == nil {
+				required = strings.TrimSpace(string(data))
+			}
+		}
+
+		ensured := make(map[string]bool)
+		ensure := func(version string) error {
+			if ensured[version] {
+				return nil
+			}
+			ensured[version] = true
+			return ensureManifestVersion(manifest, version)
+		}
+
+		var active string
+		if required != "" {
+			active, err = resolveManifestVersion(required)
+			if err != nil {
+				return fmt.Errorf("failed to resolve required version %s: %w", required, err)
+			}
+			if active != required {
+				fmt.Printf("🔎 Required version %s resolved to %s\n", required, active)
+			}
+			if err := ensure(active); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range manifest.AliasNames() {
+			version := manifest.Aliases[name]
+			if err := ensure(version); err != nil {
+				return fmt.Errorf("alias '%s': %w", name, err)
+			}
+			if personal, err := utils.ResolvePersonalAlias(name); err == nil && personal != version {
+				fmt.Printf("ℹ️  Project alias '%s' (%s) overrides your personal alias (%s) inside this project\n", name, version, personal)
+			}
+		}
+
+		if active != "" && !c.Bool("no-use") {
+			if err := utils.SetActiveVersion(active, "sync "+manifest.Path, ""); err != nil {
+				return fmt.Errorf("failed to activate %s: %w", active, err)
+			}
+			fmt.Printf("✅ Active version set to %s\n", active)
+		}
+
+		if len(manifest.Plugins) > 0 && !c.Bool("skip-plugins") {
+			version := active
+			if version == "" {
+				data, err := os.ReadFile(utils.JfvmConfig)
+				if err != nil {
+					return cli.Exit("No active version to install plugins with. Declare a version in the manifest or run 'jfvm use' first", 1)
+				}
+				version = strings.TrimSpace(string(data))
+			}
+			if err := ensurePlugins(version, manifest.Plugins); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println("✅ Project is in sync.")
+		return nil
+	},
+}
+
+// resolveManifestVersion turns the required version of a manifest into a concrete version.
+// Exact versions and names are used as is; ranges select the newest matching release.
+func resolveManifestVersion(required string) (string, error) {
+	if utils.IsReleaseVersion(required) || (!utils.IsVersionRange(required) && !strings.EqualFold(required, "latest")) {
+		return required, nil
+	}
+	return resolveTrackTarget(required)
+}
+
+// ensureManifestVersion installs a version if needed and verifies it against the manifest checksums.
+func ensureManifestVersion(manifest *utils.ProjectManifest, version string) error {
+	downloaded := false
+	if err := utils.CheckVersionExists(version); err != nil {
+		if !utils.IsReleaseVersion(version) {
+			return fmt.Errorf("version %s is not installed and cannot be downloaded", version)
+		}
+		fmt.Printf("📦 Installing %s...\n", version)
+		if err := internal.DownloadAndInstall(version); err != nil {
+			return fmt.Errorf("failed to install %s: %w", version, err)
+		}
+		downloaded = true
+	} else {
+		fmt.Printf("✅ %s is installed\n", version)
+	}
+
+	platform, err := internal.PlatformName()
+	if err != nil {
+		return err
+	}
+	expected := manifest.Checksums[version][platform]
+	if expected == "" {
+		return nil
+	}
+
+	binPath := filepath.Join(utils.JfvmVersions, version, utils.BinaryName)
+	actual, err := utils.FileSHA256(binPath)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", binPath, err)
+	}
+	if !strings.EqualFold(actual, expected) {
+		// The project pins this version, so leaving the binary in place would run it
+		mismatch := fmt.Sprintf("checksum mismatch for %s on %s: expected %s, got %s", version, platform, expected, actual)
+		if downloaded {
+			if err := os.RemoveAll(filepath.Join(utils.JfvmVersions, version)); err != nil {
+				return fmt.Errorf("%s, and removing the download failed: %w", mismatch, err)
+			}
+			return fmt.Errorf("%s. The download was removed", mismatch)
+		}
+		if _, err := utils.MoveToTrash(version); err != nil {
+			return fmt.Errorf("%s, and moving it to the trash failed: %w. Remove it with 'jfvm remove %s'", mismatch, err, version)
+		}
+		return fmt.Errorf("%s. The installed copy was moved to the trash; sync again to download a fresh one", mismatch)
+	}
+	fmt.Printf("🔒 %s matches the manifest checksum\n", version)
+	return nil
+}
+
+// ensurePlugins installs the plugins a manifest requires. Pinned plugins are reinstalled
+// unless the installed plugin reports the pinned version.
+func ensurePlugins(version string, plugins []utils.PluginRequirement) error {
+	binPath := filepath.Join(utils.JfvmVersions, version, utils.BinaryName)
+	pluginsDir := filepath.Join(utils.JfrogCliHome(), "plugins")
+
+	for _, plugin := range plugins {
+		if _, err := os.Stat(filepath.Join(pluginsDir, plugin.Name)); err == nil {
+			if plugin.Version == "" {
+				fmt.Printf("✅ Plugin %s is installed\n", plugin.Name)
+				continue
+			}
+			installed := installedPluginVersion(pluginsDir, plugin.Name)
+			if installed == strings.TrimPrefix(plugin.Version, "v") {
+				fmt.Printf("✅ Plugin %s %s is installed\n", plugin.Name, installed)
+				continue
+			}
+			if installed == "" {
+				installed = "an unknown version"
+			}
+			fmt.Printf("🔄 Plugin %s is at %s, the manifest pins %s\n", plugin.Name, installed, plugin.Version)
+		}
+
+		spec := plugin.Name
+		if plugin.Version != "" {
+			spec += "@" + plugin.Version
+		}
+		fmt.Printf("🔌 Installing plugin %s with jf %s...\n", spec, version)
+
+		cmd := exec.Command(binPath, "plugin", "install", spec)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to install plugin %s: %w", spec, err)
+		}
+
+		if plugin.Version != "" {
+			if installed := installedPluginVersion(pluginsDir, plugin.Name); installed != strings.TrimPrefix(plugin.Version, "v") {
+				return fmt.Errorf("plugin %s reports version %q after installing %s", plugin.Name, installed, spec)
+			}
+		}
+	}
+	return nil
+}
+
+var pluginVersionPattern = regexp.MustCompile(`(?i)\bversion v?(\S+)`)
+
+// installedPluginVersion returns the version an installed plugin reports with --version,
+// or an empty string when it can't be determined.
+func installedPluginVersion(pluginsDir, name string) string {
+	executable := name
+	if runtime.GOOS == "windows" {
+		executable += ".exe"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, filepath.Join(pluginsDir, name, "bin", executable), "--version").Output()
+	if err != nil {
+		return ""
+	}
+	if match := pluginVersionPattern.FindStringSubmatch(string(out)); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/jfrog/jfrog-cli-vm/internal"
+)
+
+// writeManifest writes a .jfvm.yaml to the current directory.
+func writeManifest(t *testing.T, content string) {
+	t.Helper()
+	if err := os.WriteFile(utils.ManifestFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncVerifiesChecksums(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installTestVersion(t, "2.70.0")
+	platform, err := internal.PlatformName()
+	if err != nil {
+		t.Skip(err)
+	}
+	sum, err := utils.FileSHA256(filepath.Join(utils.JfvmVersions, "2.70.0", utils.BinaryName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeManifest(t, "version: 2.70.0\nchecksums:\n  2.70.0:\n    "+platform+": "+strings.ToUpper(sum)+"\n")
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm sync"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if version := utils.GetCurrentVersion(); version != "2.70.0" {
+		t.Errorf("active version is %q, want 2.70.0", version)
+	}
+
+	writeManifest(t, "version: 2.70.0\nchecksums:\n  2.70.0:\n    "+platform+": "+strings.Repeat("0", 64)+"\n")
+	captureStdout(t, func() {
+		err = runCommandLine(t, "jfvm sync")
+	})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch for 2.70.0") {
+		t.Fatalf("got %v, want a checksum mismatch", err)
+	}
+	// The binary that fails its checksum must not stay where the shim would run it
+	if utils.CheckVersionExists("2.70.0") == nil {
+		t.Error("2.70.0 is still installed")
+	}
+	if items, _ := utils.ListTrash(); len(items) != 1 || items[0].Version != "2.70.0" {
+		t.Errorf("trash holds %v, want 2.70.0", items)
+	}
+}
+
+func TestSyncInstallsPinnedPlugins(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	t.Setenv("JFROG_CLI_HOME_DIR", t.TempDir())
+	// jf plugin install <name>@<version> leaves a plugin that reports that version
+	installTestScript(t, "2.70.0", `if [ "$1" = plugin ]; then
+  name=${3%@*}; version=${3#*@}; bin="$JFROG_CLI_HOME_DIR/plugins/$name/bin"
+  mkdir -p "$bin" && printf '#!/bin/sh\necho "%s version %s"\n' "$name" "$version" > "$bin/$name" && chmod +x "$bin/$name"
+  echo "installed $3"
+fi`)
+
+	writeManifest(t, "version: 2.70.0\nplugins:\n  - name: rt-fs\n    version: 1.0.0\n")
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm sync"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "installed rt-fs@1.0.0") {
+		t.Errorf("plugin not installed:\n%s", stdout)
+	}
+	pluginsDir := filepath.Join(os.Getenv("JFROG_CLI_HOME_DIR"), "plugins")
+	if version := installedPluginVersion(pluginsDir, "rt-fs"); version != "1.0.0" {
+		t.Errorf("rt-fs reports %q, want 1.0.0", version)
+	}
+
+	writeManifest(t, "version: 2.70.0\nplugins:\n  - name: rt-fs\n    version: 1.1.0\n")
+	stdout = captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm sync"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "Plugin rt-fs is at 1.0.0, the manifest pins 1.1.0") {
+		t.Errorf("pinned version not enforced:\n%s", stdout)
+	}
+	if version := installedPluginVersion(pluginsDir, "rt-fs"); version != "1.1.0" {
+		t.Errorf("rt-fs reports %q, want 1.1.0", version)
+	}
+
+	stdout = captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm sync"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Contains(stdout, "installed rt-fs") {
+		t.Errorf("an up-to-date plugin was reinstalled:\n%s", stdout)
+	}
+}
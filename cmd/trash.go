@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var Trash = &cli.Command{
+	Name:        "trash",
+	Usage:       descriptions.Trash.Usage,
+	Description: descriptions.Trash.Format(),
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List removed versions that can be restored",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "no-color",
+					Usage: "Disable colored output",
+					Value: false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.Bool("no-color") {
+					color.NoColor = true
+				}
+				if _, err := utils.ExpireTrash(); err != nil {
+					return fmt.Errorf("failed to expire trash: %w", err)
+				}
+				entries, err := utils.ListTrash()
+				if err != nil {
+					return fmt.Errorf("failed to read trash: %w", err)
+				}
+				if len(entries) == 0 {
+					fmt.Println("📭 The trash is empty.")
+					return nil
+				}
+				displayTrash(entries)
+				return nil
+			},
+		},
+		{
+			Name:  "empty",
+			Usage: "Permanently delete trashed release versions",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "include-linked",
+					Usage: "Also delete trashed linked builds, which cannot be re-downloaded",
+					Value: false,
+				},
+				&cli.BoolFlag{
+					Name:    "yes",
+					Aliases: []string{"y"},
+					Usage:   "Don't ask for confirmation",
+					Value:   false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				entries, err := utils.ListTrash()
+				if err != nil {
+					return fmt.Errorf("failed to read trash: %w", err)
+				}
+
+				var targets []utils.TrashEntry
+				var remove []string
+				keep := []string{utils.JfvmVersions}
+				for _, entry := range entries {
+					dir := filepath.Join(utils.JfvmTrash, entry.ID)
+					if entry.Linked && !c.Bool("include-linked") {
+						fmt.Printf("🛡️  Keeping linked build %s; use --include-linked to delete it\n", entry.Version)
+						keep = append(keep, dir)
+						continue
+					}
+					targets = append(targets, entry)
+					remove = append(remove, dir)
+				}
+				if len(targets) == 0 {
+					fmt.Println("✅ Nothing to delete.")
+					return nil
+				}
+				// Binaries still hard-linked from installed versions or kept entries free nothing
+				total, _ := utils.ReclaimableSize(remove, keep)
+				if !c.Bool("yes") && !utils.Confirm(fmt.Sprintf("Permanently delete %d trashed versions (%s)?", len(targets), utils.FormatBytes(total))) {
+					fmt.Println("Aborted.")
+					return nil
+				}
+
+				for _, entry := range targets {
+					if err := utils.DeleteFromTrash(entry); err != nil {
+						return fmt.Errorf("failed to delete %s: %w", entry.Version, err)
+					}
+				}
+				fmt.Printf("🗑️  Deleted %d versions and reclaimed %s\n", len(targets), utils.FormatBytes(total))
+				return nil
+			},
+		},
+	},
+}
+
+var Restore = &cli.Command{
+	Name:        "restore",
+	Usage:       descriptions.Restore.Usage,
+	ArgsUsage:   "<version>",
+	Description: descriptions.Restore.Format(),
+	Action: func(c *cli.Context) error {
+		if c.Args().Len() != 1 {
+			return cli.Exit("Usage: jfvm restore <version>", 1)
+		}
+		version := c.Args().Get(0)
+
+		entry, err := utils.RestoreFromTrash(version)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		fmt.Printf("♻️  Restored %s (removed %s)\n", version, entry.TrashedAt.Format("2006-01-02 15:04"))
+		return nil
+	},
+}
+
+func displayTrash(entries []utils.TrashEntry) {
+	var (
+		greenColor  = color.New(color.FgGreen)
+		yellowColor = color.New(color.FgYellow)
+	)
+	retention := utils.TrashRetention()
+
+	fmt.Printf("%-15s %-17s %-10s %s\n", "VERSION", "REMOVED", "SIZE", "EXPIRES")
+	var total int64
+	for _, entry := range entries {
+		expires := greenColor.Sprint("never (linked build)")
+		if !entry.Linked {
+			remaining := time.Until(entry.TrashedAt.Add(retention))
+			expires = yellowColor.Sprintf("in %s", formatRemaining(remaining))
+		}
+		fmt.Printf("%-15s %-17s %-10s %s\n", entry.Version, entry.TrashedAt.Format("2006-01-02 15:04"), utils.FormatBytes(entry.Size), expires)
+		total += entry.Size
+	}
+	fmt.Printf("\n%d versions, %s in total. Restore with 'jfvm restore <version>'.\n", len(entries), utils.FormatBytes(total))
+}
+
+func formatRemaining(d time.Duration) string {
+	if d < time.Hour {
+		return "less than an hour"
+	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestTrashEmptyKeepsLinkedBuilds(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "my-build"} {
+		installTestVersion(t, version)
+		if _, err := utils.MoveToTrash(version); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout := captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm trash empty --yes"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(stdout, "Keeping linked build my-build") || !strings.Contains(stdout, "Deleted 1 versions") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+	entries, _ := utils.ListTrash()
+	if len(entries) != 1 || entries[0].Version != "my-build" {
+		t.Fatalf("trash holds %+v, want my-build", entries)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, "jfvm restore my-build"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := utils.CheckVersionExists("my-build"); err != nil {
+		t.Errorf("my-build was not restored: %v", err)
+	}
+	if err := runCommandLine(t, "jfvm restore 2.70.0"); err == nil {
+		t.Error("restored a version that was deleted for good")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/jfrog/jfrog-cli-vm/cmd/descriptions"
 	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
 	"github.com/jfrog/jfrog-cli-vm/internal"
@@ -15,18 +16,54 @@ import (
 var Use = &cli.Command{
 	Name:        "use",
 	Usage:       descriptions.Use.Usage,
-	ArgsUsage:   "[version or alias] (optional if .jfrog-version exists)",
+	ArgsUsage:   "[version, alias or -] (optional if .jfrog-version exists)",
 	Description: descriptions.Use.Format(),
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "history",
+			Usage: "Show recent version switches",
+			Value: false,
+		},
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Note recorded with the switch in the journal",
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colored output",
+			Value: false,
+		},
+	},
 	Action: func(c *cli.Context) error {
+		if c.Bool("history") {
+			return displaySwitchJournal(20, c.Bool("no-color"))
+		}
+
+		if err := misplacedFlagError(c); err != nil {
+			return err
+		}
+		if c.Args().Len() > 1 {
+			return cli.Exit("Usage: jfvm use [--reason <text>] [version, alias or -]", 1)
+		}
+
 		fmt.Println("Executing 'jfvm use' command...")
-		var version string
+		var version, source string
 
 		if c.Args().Len() == 1 {
 			v := c.Args().Get(0)
 			fmt.Printf("Received argument: %s\n", v)
 
-			// Handle "latest" parameter
-			if strings.ToLower(v) == "latest" {
+			if v == "-" {
+				previous, err := versionBeforeSwitch(1)
+				if err != nil {
+					return cli.Exit(err.Error(), 1)
+				}
+				version = previous
+				source = "use -"
+				fmt.Printf("Switching back to previous version: %s\n", version)
+			} else if strings.ToLower(v) == "latest" {
+				// Handle "latest" parameter
+				source = "latest"
 				fmt.Println("Fetching latest version...")
 				latestVersion, err := utils.GetLatestVersion()
 				if err != nil {
@@ -47,22 +84,41 @@ var Use = &cli.Command{
 				}
 			} else {
 				// Try to resolve alias (silently fallback if not found)
+				refreshAliasIfDue(v)
 				resolved, err := utils.ResolveAlias(v)
 				if err == nil {
 					version = strings.TrimSpace(resolved)
+					source = fmt.Sprintf("alias '%s'", v)
 					fmt.Printf("Using alias '%s' resolved to version: %s\n", v, version)
 				} else {
 					// don't log anything — just fallback silently
 					version = v
+					source = "argument"
 				}
 			}
 		} else {
 			v, err := utils.GetVersionFromProjectFile()
-			if err != nil {
-				return cli.Exit("No version provided and no .jfrog-version file found", 1)
+			if err == nil {
+				version = v
+				source = utils.ProjectFile
+				utils.RecordProjectFile(utils.ProjectFile)
+				fmt.Printf("Using version from .jfrog-version: %s\n", version)
+			} else {
+				manifest, manifestErr := utils.FindProjectManifest()
+				if manifestErr != nil {
+					return manifestErr
+				}
+				if manifest == nil || manifest.Version == "" {
+					return cli.Exit("No version provided and no .jfrog-version or .jfvm.yaml file found", 1)
+				}
+				version, err = resolveManifestVersion(manifest.Version)
+				if err != nil {
+					return fmt.Errorf("failed to resolve %s from %s: %w", manifest.Version, manifest.Path, err)
+				}
+				source = manifest.Path
+				utils.RecordProjectFile(manifest.Path)
+				fmt.Printf("Using version from %s: %s\n", manifest.Path, version)
 			}
-			version = v
-			fmt.Printf("Using version from .jfrog-version: %s\n", version)
 		}
 
 		// For non-latest versions, check if binary exists and install if needed
@@ -79,6 +135,73 @@ var Use = &cli.Command{
 		}
 
 		fmt.Printf("Writing selected version '%s' to config file: %s\n", version, utils.JfvmConfig)
-		return os.WriteFile(utils.JfvmConfig, []byte(version), 0644)
+		return utils.SetActiveVersion(version, source, c.String("reason"))
 	},
 }
+
+// versionBeforeSwitch returns the version that was active before the n-th most recent switch.
+func versionBeforeSwitch(n int) (string, error) {
+	entries, err := utils.LoadJournal()
+	if err != nil {
+		return "", fmt.Errorf("failed to read switch journal: %w", err)
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no version switches recorded yet")
+	}
+	if n < 1 || n > len(entries) {
+		return "", fmt.Errorf("only %d version switches are recorded", len(entries))
+	}
+
+	entry := entries[len(entries)-n]
+	if entry.From == "" {
+		return "", fmt.Errorf("no version was active before switch #%d", n)
+	}
+	return entry.From, nil
+}
+
+func displaySwitchJournal(limit int, noColor bool) error {
+	if noColor {
+		color.NoColor = true
+	}
+
+	entries, err := utils.LoadJournal()
+	if err != nil {
+		return fmt.Errorf("failed to read switch journal: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("📭 No version switches recorded.")
+		return nil
+	}
+
+	var (
+		blueColor  = color.New(color.FgBlue)
+		greenColor = color.New(color.FgGreen)
+		redColor   = color.New(color.FgRed)
+	)
+
+	fmt.Printf("🔀 VERSION SWITCHES\n")
+	fmt.Printf("═══════════════════════════════════════════════════════════════════════════════════\n\n")
+	fmt.Printf("%-4s %-20s %-15s %-15s %s\n", "#", "TIMESTAMP", "FROM", "TO", "SOURCE")
+	fmt.Printf("─────────────────────────────────────────────────────────────────────────────────────\n")
+
+	for i := 0; i < limit && i < len(entries); i++ {
+		entry := entries[len(entries)-1-i]
+		from := entry.From
+		if from == "" {
+			from = "-"
+		}
+		source := entry.Source
+		if entry.Reason != "" {
+			source = fmt.Sprintf("%s: %s", source, entry.Reason)
+		}
+		fmt.Printf("%-4d %s %s %s %s\n",
+			i+1,
+			blueColor.Sprintf("%-20s", entry.Timestamp.Format("2006-01-02 15:04:05")),
+			redColor.Sprintf("%-15s", from),
+			greenColor.Sprintf("%-15s", entry.To),
+			source)
+	}
+
+	fmt.Printf("\n↩️  'jfvm use -' restores the FROM version of #1, 'jfvm rollback N' the FROM version of #N\n")
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-vm/cmd/utils"
+)
+
+func TestUseRejectsFlagsAfterVersion(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installTestVersion(t, "2.73.0")
+	installTestVersion(t, "2.74.0")
+	if err := os.WriteFile(utils.ProjectFile, []byte("2.73.0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() {
+		if err := runCommandLine(t, `jfvm use 2.74.0 --reason "ci pin"`); err == nil {
+			t.Error("expected an error for --reason after the version")
+		}
+		if err := runCommandLine(t, "jfvm use 2.74.0 2.73.0"); err == nil {
+			t.Error("expected an error for two versions")
+		}
+	})
+	if current := utils.GetCurrentVersion(); current != "" {
+		t.Errorf("active version switched to %s", current)
+	}
+}
+
+func TestUseRecordsReasonAndSwitchesBack(t *testing.T) {
+	useTestRoot(t)
+	t.Chdir(t.TempDir())
+	installTestVersion(t, "2.73.0")
+	installTestVersion(t, "2.74.0")
+
+	captureStdout(t, func() {
+		for _, line := range []string{"jfvm use 2.73.0", `jfvm use --reason "ci pin" 2.74.0`, "jfvm use -"} {
+			if err := runCommandLine(t, line); err != nil {
+				t.Fatalf("%s: %v", line, err)
+			}
+		}
+	})
+
+	if current := utils.GetCurrentVersion(); current != "2.73.0" {
+		t.Errorf("use - left %s active, want 2.73.0", current)
+	}
+	entries, err := utils.LoadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("journal has %d entries, want 3", len(entries))
+	}
+	if entries[1].To != "2.74.0" || entries[1].Reason != "ci pin" || entries[1].Source != "argument" {
+		t.Errorf("second switch recorded as %+v", entries[1])
+	}
+	if entries[2].Source != "use -" {
+		t.Errorf("use - recorded with source %q", entries[2].Source)
+	}
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ReservedAliases are keywords that have a meaning of their own wherever a version is accepted.
+var ReservedAliases = []string{"latest"}
+
+// ValidateAliasName rejects names that would escape the aliases directory, reserved keywords,
+// and names that look like release versions, since those would shadow the real version.
+func ValidateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name '%s': use letters, digits, '.', '_' and '-', starting with a letter or digit", name)
+	}
+	for _, reserved := range ReservedAliases {
+		if strings.EqualFold(name, reserved) {
+			return fmt.Errorf("'%s' is a reserved keyword and cannot be used as an alias", name)
+		}
+	}
+	if IsReleaseVersion(name) {
+		return fmt.Errorf("'%s' looks like a version number and cannot be used as an alias", name)
+	}
+	return nil
+}
+
+// ListAliases returns every personal alias name mapped to its target version, sorted by name.
+func ListAliases() ([]string, map[string]string, error) {
+	entries, err := os.ReadDir(JfvmAliases)
+	if os.IsNotExist(err) {
+		return nil, map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	targets := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		target, err := ResolvePersonalAlias(entry.Name())
+		if err != nil {
+			continue
+		}
+		names = append(names, entry.Name())
+		targets[entry.Name()] = target
+	}
+	sort.Strings(names)
+	return names, targets, nil
+}
+
+// AliasesForVersion returns the personal and project aliases that point at the given version.
+func AliasesForVersion(version string) []string {
+	var aliases []string
+	if names, targets, err := ListAliases(); err == nil {
+		for _, name := range names {
+			if targets[name] == version {
+				aliases = append(aliases, name)
+			}
+		}
+	}
+	if manifest, err := FindProjectManifest(); err == nil && manifest != nil {
+		for _, name := range manifest.AliasNames() {
+			if manifest.Aliases[name] == version {
+				aliases = append(aliases, name+" (project)")
+			}
+		}
+	}
+	return aliases
+}
+
+// maxAliasHistory bounds how many previous targets are kept for rollback.
+const maxAliasHistory = 20
+
+// AliasChange records a target an alias pointed at before it was moved.
+type AliasChange struct {
+	Version    string    `json:"version"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// AliasMeta holds everything about an alias besides its current target: the version or range
+// it tracks, how often it is re-resolved, and its previous targets.
+type AliasMeta struct {
+	Track           string        `json:"track,omitempty"`
+	RefreshInterval string        `json:"refresh_interval,omitempty"`
+	LastRefresh     time.Time     `json:"last_refresh,omitempty"`
+	Held            bool          `json:"held,omitempty"`
+	Previous        []AliasChange `json:"previous,omitempty"`
+}
+
+// RefreshDue reports whether a tracking alias with a refresh interval should be re-resolved.
+func (m AliasMeta) RefreshDue() bool {
+	if m.Track == "" || m.Held || m.RefreshInterval == "" {
+		return false
+	}
+	interval, err := time.ParseDuration(m.RefreshInterval)
+	if err != nil {
+		return false
+	}
+	return time.Since(m.LastRefresh) >= interval
+}
+
+func LoadAliasMeta(name string) (AliasMeta, error) {
+	var meta AliasMeta
+	data, err := os.ReadFile(filepath.Join(JfvmAliasMeta, name+".json"))
+	if os.IsNotExist(err) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, err
+	}
+	return meta, json.Unmarshal(data, &meta)
+}
+
+func SaveAliasMeta(name string, meta AliasMeta) error {
+	if err := os.MkdirAll(JfvmAliasMeta, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(JfvmAliasMeta, name+".json"), data, 0644)
+}
+
+// WriteAlias points an alias at a version, creating the aliases directory if needed.
+// The previous target is remembered so the change can be rolled back.
+func WriteAlias(name, version string) error {
+	if err := ValidateAliasName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(JfvmAliases, 0755); err != nil {
+		return err
+	}
+
+	if previous, err := ResolvePersonalAlias(name); err == nil && previous != version {
+		meta, err := LoadAliasMeta(name)
+		if err != nil {
+			return err
+		}
+		meta.Previous = append(meta.Previous, AliasChange{Version: previous, ReplacedAt: time.Now()})
+		if len(meta.Previous) > maxAliasHistory {
+			meta.Previous = meta.Previous[len(meta.Previous)-maxAliasHistory:]
+		}
+		if err := SaveAliasMeta(name, meta); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(filepath.Join(JfvmAliases, name), []byte(version), 0644)
+}
+
+// RollbackAlias points an alias back at its previous target and returns that version.
+func RollbackAlias(name string) (string, error) {
+	meta, err := LoadAliasMeta(name)
+	if err != nil {
+		return "", err
+	}
+	if len(meta.Previous) == 0 {
+		return "", fmt.Errorf("alias '%s' has no previous target to roll back to", name)
+	}
+
+	previous := meta.Previous[len(meta.Previous)-1]
+	meta.Previous = meta.Previous[:len(meta.Previous)-1]
+	if err := os.WriteFile(filepath.Join(JfvmAliases, name), []byte(previous.Version), 0644); err != nil {
+		return "", err
+	}
+	return previous.Version, SaveAliasMeta(name, meta)
+}
+
+// RemoveAlias deletes an alias together with its metadata.
+func RemoveAlias(name string) error {
+	if err := os.Remove(filepath.Join(JfvmAliases, name)); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(JfvmAliasMeta, name+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
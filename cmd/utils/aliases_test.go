@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidateAliasName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"prod":      true,
+		"team.ci_1": true,
+		"v2-beta":   true,
+		"":          false,
+		"../escape": false,
+		"-dash":     false,
+		"a/b":       false,
+		"latest":    false,
+		"LATEST":    false,
+		"2.74.0":    false,
+	} {
+		if err := ValidateAliasName(name); (err == nil) != valid {
+			t.Errorf("%q: got %v, want valid %v", name, err, valid)
+		}
+	}
+}
+
+func TestListAliasesAndAliasesForVersion(t *testing.T) {
+	useTestRoot(t)
+	for name, version := range map[string]string{"prod": "2.70.0", "ci": "2.71.0", "old": "2.70.0"} {
+		if err := WriteAlias(name, version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directories in the aliases directory are not aliases
+	if err := os.Mkdir(filepath.Join(JfvmAliases, "stray"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	names, targets, err := ListAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"ci", "old", "prod"}) || targets["ci"] != "2.71.0" {
+		t.Errorf("got %v %v", names, targets)
+	}
+	if got := AliasesForVersion("2.70.0"); !slices.Equal(got, []string{"old", "prod"}) {
+		t.Errorf("aliases for 2.70.0: got %v", got)
+	}
+	if got := AliasesForVersion("2.72.0"); len(got) != 0 {
+		t.Errorf("aliases for 2.72.0: got %v", got)
+	}
+}
+
+func TestWriteAliasRejectsInvalidNames(t *testing.T) {
+	useTestRoot(t)
+	if err := WriteAlias("../config", "2.70.0"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := os.Stat(JfvmConfig); err == nil {
+		t.Error("alias escaped the aliases directory")
+	}
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const versionCheckTimeout = 10 * time.Second
+
+var cliVersionPattern = regexp.MustCompile(`(?i)\bjf(?:rog)?(?:\.exe)? version v?(\S+)`)
+
+// IsShimPath reports whether path resolves to the jfvm shim, which must never be linked
+// as a version: it would exec itself forever.
+func IsShimPath(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	shim, err := os.Stat(filepath.Join(JfvmShim, BinaryName))
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info, shim)
+}
+
+// ValidateExecutable checks that path is a regular, executable binary built for the host
+// platform.
+func ValidateExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory, not a jf binary", path)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("%s is empty", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("%s is not executable (try chmod +x)", path)
+	}
+
+	format, arch, err := binaryPlatform(path)
+	if err != nil {
+		return fmt.Errorf("%s is not an executable binary: %w", path, err)
+	}
+	if format != hostBinaryFormat() {
+		return fmt.Errorf("%s is a %s binary, but this is %s", path, format, runtime.GOOS)
+	}
+	if arch != "" && arch != runtime.GOARCH {
+		return fmt.Errorf("%s is built for %s, but this machine is %s", path, arch, runtime.GOARCH)
+	}
+	return nil
+}
+
+func hostBinaryFormat() string {
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		return "Mach-O"
+	case "windows":
+		return "PE"
+	default:
+		return "ELF"
+	}
+}
+
+// binaryPlatform returns the executable format of path and its GOARCH, which is empty when
+// the architecture is one jfvm doesn't know about.
+func binaryPlatform(path string) (string, string, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		archs := map[elf.Machine]string{
+			elf.EM_X86_64:  "amd64",
+			elf.EM_386:     "386",
+			elf.EM_AARCH64: "arm64",
+			elf.EM_ARM:     "arm",
+			elf.EM_S390:    "s390x",
+			elf.EM_RISCV:   "riscv64",
+		}
+		arch := archs[f.Machine]
+		if f.Machine == elf.EM_PPC64 {
+			arch = "ppc64"
+			if f.ByteOrder.String() == "LittleEndian" {
+				arch = "ppc64le"
+			}
+		}
+		return "ELF", arch, nil
+	}
+
+	machoArchs := map[macho.Cpu]string{macho.CpuAmd64: "amd64", macho.CpuArm64: "arm64"}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return "Mach-O", machoArchs[f.Cpu], nil
+	}
+	if f, err := macho.OpenFat(path); err == nil {
+		defer f.Close()
+		// A universal binary runs on the host if any of its slices does
+		for _, a := range f.Arches {
+			if machoArchs[a.Cpu] == runtime.GOARCH {
+				return "Mach-O", runtime.GOARCH, nil
+			}
+		}
+		return "Mach-O", machoArchs[f.Arches[0].Cpu], nil
+	}
+
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		archs := map[uint16]string{
+			pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+			pe.IMAGE_FILE_MACHINE_I386:  "386",
+			pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+		}
+		return "PE", archs[f.Machine], nil
+	}
+
+	data := make([]byte, 2)
+	if file, err := os.Open(path); err == nil {
+		_, _ = file.Read(data)
+		_ = file.Close()
+	}
+	if string(data) == "#!" {
+		return "", "", fmt.Errorf("it is a script")
+	}
+	return "", "", fmt.Errorf("unknown file format")
+}
+
+// ReportedCLIVersion runs `<path> --version` and returns the version JFrog CLI reports.
+// It fails when the binary doesn't identify itself as JFrog CLI.
+func ReportedCLIVersion(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--version")
+	// A copy of the shim must fail fast instead of recursing, so pretend it is deeply nested
+	cmd.Env = append(os.Environ(), "JFVM_SHIM_DEPTH=999")
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s --version did not finish within %s", path, versionCheckTimeout)
+	}
+
+	if match := cliVersionPattern.FindStringSubmatch(string(out)); match != nil {
+		return match[1], nil
+	}
+
+	output := strings.TrimSpace(string(out))
+	if len(output) > 200 {
+		output = output[:200] + "..."
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s --version failed (%v): %s", path, err, output)
+	}
+	return "", fmt.Errorf("%s doesn't look like JFrog CLI: --version printed %q", path, output)
+}
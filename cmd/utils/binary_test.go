@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateExecutable(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, err string
+	}{
+		{self, ""},
+		{dir, "is a directory"},
+		{write("empty", "", 0755), "is empty"},
+		{write("plain", "\x7fELF", 0644), "is not executable"},
+		{write("script", "#!/bin/sh\necho jf version 2.74.0\n", 0755), "it is a script"},
+		{write("garbage", "MZ-not-really", 0755), "unknown file format"},
+		{filepath.Join(dir, "missing"), "no such file"},
+	}
+	for _, test := range tests {
+		err := ValidateExecutable(test.path)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.path, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: got %v, want %q", test.path, err, test.err)
+		}
+	}
+}
+
+func TestReportedCLIVersion(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		script, version, err string
+	}{
+		{`echo "jf version 2.74.0"`, "2.74.0", ""},
+		{`echo "jfrog version v2.74.1"`, "2.74.1", ""},
+		// A copy of the shim must be told to stop instead of running itself
+		{`[ "$JFVM_SHIM_DEPTH" = 999 ] && echo "recursion" >&2 && exit 1; echo "jf version 1.0.0"`, "", "--version failed"},
+		{`echo "git version 2.43.0"`, "", "doesn't look like JFrog CLI"},
+	}
+	for i, test := range tests {
+		path := filepath.Join(dir, "jf"+string(rune('a'+i)))
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+test.script+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		version, err := ReportedCLIVersion(path)
+		if version != test.version || (test.err == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%s: got %q, %v, want %q, %q", test.script, version, err, test.version, test.err)
+		}
+	}
+}
+
+func TestIsShimPath(t *testing.T) {
+	useTestRoot(t)
+	if err := os.MkdirAll(JfvmShim, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shim := filepath.Join(JfvmShim, BinaryName)
+	if err := os.WriteFile(shim, []byte("shim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "jf")
+	if err := os.Symlink(shim, link); err != nil {
+		t.Fatal(err)
+	}
+	other := installTestBinary(t, "2.70.0", "shim")
+
+	if !IsShimPath(shim) || !IsShimPath(link) {
+		t.Error("the shim or a link to it was not recognized")
+	}
+	if IsShimPath(other) {
+		t.Error("a copy with the same content is not the shim")
+	}
+}
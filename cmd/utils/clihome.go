@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// isolatedHomeSkips are parts of the JFrog CLI home that are never copied into an isolated
+// home: logs are large and irrelevant, and lock files would block the isolated run.
+var isolatedHomeSkips = []string{"logs", "locks"}
+
+// NewIsolatedCliHome creates a temporary JFrog CLI home seeded with a copy of the user's
+// configuration, so a version can migrate or lock it without affecting other versions.
+// cleanup removes the copy.
+func NewIsolatedCliHome(label string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "jfvm-home-"+label+"-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create isolated JFrog CLI home: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	src := JfrogCliHome()
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return dir, cleanup, nil
+	}
+	err = CopyTree(src, dir, func(rel string, d fs.DirEntry) bool {
+		return (d.IsDir() && slices.Contains(isolatedHomeSkips, rel)) || strings.HasSuffix(d.Name(), ".lock")
+	})
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to seed isolated JFrog CLI home: %w", err)
+	}
+	return dir, cleanup, nil
+}
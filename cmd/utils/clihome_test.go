@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewIsolatedCliHomeCopiesConfiguration(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("JFROG_CLI_HOME_DIR", home)
+	for name, content := range map[string]string{
+		"jfrog-cli.conf.v6":        `{"servers": []}`,
+		"plugins/rt-fs/bin/rt-fs":  "#!/bin/sh",
+		"logs/jfrog-cli.log":       "old log",
+		"locks/config/1.lock":      "",
+		"security/certs/ca.pem":    "cert",
+		"security/config.lock":     "",
+		"plugins/rt-fs/bin/x.lock": "",
+	} {
+		path := filepath.Join(home, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir, cleanup, err := NewIsolatedCliHome("2.70.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, copied := range map[string]bool{
+		"jfrog-cli.conf.v6":        true,
+		"plugins/rt-fs/bin/rt-fs":  true,
+		"security/certs/ca.pem":    true,
+		"logs":                     false,
+		"locks":                    false,
+		"security/config.lock":     false,
+		"plugins/rt-fs/bin/x.lock": false,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); (err == nil) != copied {
+			t.Errorf("%s: copied is %v, want %v", name, err == nil, copied)
+		}
+	}
+
+	// The copy is the version's own: changes don't reach the user's home
+	if err := os.WriteFile(filepath.Join(dir, "jfrog-cli.conf.v6"), []byte("migrated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(home, "jfrog-cli.conf.v6")); string(data) != `{"servers": []}` {
+		t.Errorf("the user's configuration changed to %s", data)
+	}
+
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("cleanup left the isolated home behind")
+	}
+}
+
+func TestNewIsolatedCliHomeWithoutConfiguration(t *testing.T) {
+	t.Setenv("JFROG_CLI_HOME_DIR", filepath.Join(t.TempDir(), "missing"))
+	dir, cleanup, err := NewIsolatedCliHome("2.70.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) > 0 {
+		t.Errorf("isolated home holds %v (%v), want an empty directory", entries, err)
+	}
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// DedupResult describes one binary that was (or would be) replaced by a hard link.
+type DedupResult struct {
+	Version  string
+	LinkedTo string
+	Size     int64
+}
+
+// versionBinaries maps installed versions to the info of their jf binary.
+func versionBinaries() (map[string]os.FileInfo, []string, error) {
+	versions, err := ListInstalledVersions()
+	if err != nil {
+		return nil, nil, err
+	}
+	infos := make(map[string]os.FileInfo)
+	var present []string
+	for _, version := range versions {
+		// Lstat skips symlinked versions, which must keep pointing at their build output
+		info, err := os.Lstat(filepath.Join(JfvmVersions, version, BinaryName))
+		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+			continue
+		}
+		infos[version] = info
+		present = append(present, version)
+	}
+	return infos, present, nil
+}
+
+// DedupBinaries replaces byte-identical jf binaries across version directories with hard
+// links to a single copy. Only version is considered when it is non-empty, otherwise all
+// versions are. With dryRun nothing is changed.
+func DedupBinaries(version string, dryRun bool) ([]DedupResult, error) {
+	infos, versions, err := versionBinaries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hashing is expensive, so only binaries that share a size are compared by content
+	bySize := make(map[int64][]string)
+	for _, v := range versions {
+		bySize[infos[v].Size()] = append(bySize[infos[v].Size()], v)
+	}
+
+	var results []DedupResult
+	for size, group := range bySize {
+		if len(group) < 2 {
+			continue
+		}
+
+		bySum := make(map[string][]string)
+		var sums []string
+		for _, v := range group {
+			sum, err := FileSHA256(filepath.Join(JfvmVersions, v, BinaryName))
+			if err != nil {
+				return results, err
+			}
+			if _, seen := bySum[sum]; !seen {
+				sums = append(sums, sum)
+			}
+			bySum[sum] = append(bySum[sum], v)
+		}
+
+		for _, sum := range sums {
+			identical := bySum[sum]
+			if len(identical) < 2 {
+				continue
+			}
+
+			source, targets := identical[0], identical[1:]
+			if version != "" {
+				// Link only the requested version, to the first other identical copy
+				if !slices.Contains(identical, version) {
+					continue
+				}
+				source, targets = identical[0], []string{version}
+				if source == version {
+					source = identical[1]
+				}
+			}
+
+			for _, target := range targets {
+				if os.SameFile(infos[source], infos[target]) {
+					continue
+				}
+				if !dryRun {
+					if err := replaceWithLink(filepath.Join(JfvmVersions, source, BinaryName), filepath.Join(JfvmVersions, target, BinaryName)); err != nil {
+						return results, fmt.Errorf("failed to link %s to %s: %w", target, source, err)
+					}
+				}
+				results = append(results, DedupResult{Version: target, LinkedTo: source, Size: size})
+			}
+		}
+	}
+	return results, nil
+}
+
+// replaceWithLink atomically swaps path for a hard link to source.
+func replaceWithLink(source, path string) error {
+	tmp := path + ".jfvm-link"
+	_ = os.Remove(tmp)
+	if err := os.Link(source, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	// The link shares the source's mode, and every copy must stay executable
+	return os.Chmod(path, 0755)
+}
+
+// CreateFileReplacing creates path for writing without truncating an existing file in place,
+// which would also change every hard link that shares its content.
+func CreateFileReplacing(path string) (*os.File, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// ReclaimableSize returns how many bytes deleting the remove directories frees. A file
+// hard-linked from several of them is counted once, and not at all while a link to it
+// remains below one of the keep directories.
+func ReclaimableSize(remove, keep []string) (int64, error) {
+	// Files are bucketed by size so only candidates are compared with os.SameFile
+	files := make(map[int64][]os.FileInfo)
+	for _, dir := range remove {
+		err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			for _, other := range files[info.Size()] {
+				if os.SameFile(other, info) {
+					return nil
+				}
+			}
+			files[info.Size()] = append(files[info.Size()], info)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, dir := range keep {
+		err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			files[info.Size()] = slices.DeleteFunc(files[info.Size()], func(other os.FileInfo) bool {
+				return os.SameFile(other, info)
+			})
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var size int64
+	for _, infos := range files {
+		for _, info := range infos {
+			size += info.Size()
+		}
+	}
+	return size, nil
+}
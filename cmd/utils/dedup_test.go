@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReclaimableSizeCountsHardLinksOnce(t *testing.T) {
+	root := t.TempDir()
+	write := func(name string, size int) string {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	link := func(source, name string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Link(source, path); err != nil {
+			t.Skipf("hard links unsupported: %v", err)
+		}
+	}
+
+	shared := write("a/jf", 100)
+	link(shared, "b/jf")
+	write("c/jf", 100)
+	dir := func(name string) string { return filepath.Join(root, name) }
+
+	tests := []struct {
+		name         string
+		remove, keep []string
+		want         int64
+	}{
+		{"linked copy kept", []string{dir("a")}, []string{dir("b"), dir("c")}, 0},
+		{"both links removed", []string{dir("a"), dir("b")}, []string{dir("c")}, 100},
+		{"distinct file", []string{dir("b"), dir("c")}, []string{dir("a")}, 100},
+		{"everything", []string{dir("a"), dir("b"), dir("c")}, []string{dir("missing")}, 200},
+	}
+	for _, test := range tests {
+		got, err := ReclaimableSize(test.remove, test.keep)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %d bytes, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDedupBinaries(t *testing.T) {
+	useTestRoot(t)
+	a := installTestBinary(t, "2.70.0", "same binary")
+	b := installTestBinary(t, "2.71.0", "same binary")
+	c := installTestBinary(t, "2.72.0", "same binary")
+	installTestBinary(t, "2.73.0", "other binary")
+	// A symlinked build must keep pointing at its build output
+	if err := os.MkdirAll(filepath.Join(JfvmVersions, "my-build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(a, filepath.Join(JfvmVersions, "my-build", BinaryName)); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := DedupBinaries("", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("dry run: got %+v, want 2 links", results)
+	}
+	if sameFile(t, a, b) {
+		t.Fatal("dry run linked binaries")
+	}
+
+	if results, err = DedupBinaries("2.71.0", false); err != nil || len(results) != 1 || results[0].LinkedTo != "2.70.0" {
+		t.Fatalf("single version: got %+v, %v", results, err)
+	}
+	if !sameFile(t, a, b) || sameFile(t, a, c) {
+		t.Error("only 2.71.0 should be linked")
+	}
+
+	if _, err = DedupBinaries("", false); err != nil {
+		t.Fatal(err)
+	}
+	if !sameFile(t, a, c) {
+		t.Error("2.72.0 was not linked")
+	}
+	if info, err := os.Lstat(filepath.Join(JfvmVersions, "my-build", BinaryName)); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("the symlinked build was replaced")
+	}
+	if results, _ := DedupBinaries("", false); len(results) != 0 {
+		t.Errorf("second run linked %+v again", results)
+	}
+}
+
+func TestCreateFileReplacingLeavesLinksAlone(t *testing.T) {
+	dir := t.TempDir()
+	shared, other := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(shared, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(shared, other); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	file, err := CreateFileReplacing(shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString("new")
+	_ = file.Close()
+	if data, _ := os.ReadFile(other); string(data) != "old" {
+		t.Errorf("the other link now reads %q", data)
+	}
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA != nil || errB != nil {
+		t.Fatal(errA, errB)
+	}
+	return os.SameFile(infoA, infoB)
+}
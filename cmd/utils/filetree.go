@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTextFileSize is the largest text file whose content is kept for diffs; larger files
+// are compared by checksum only.
+const maxTextFileSize = 256 << 10
+
+// FileEntry describes one file, directory or symlink of a scanned tree.
+type FileEntry struct {
+	// Path is relative to the scanned root, with forward slashes
+	Path   string      `json:"path"`
+	Mode   fs.FileMode `json:"mode"`
+	Size   int64       `json:"size,omitempty"`
+	SHA256 string      `json:"sha256,omitempty"`
+	Link   string      `json:"link,omitempty"`
+	// Text is the content of small text files, kept so changes can be shown as a diff
+	Text   string `json:"text,omitempty"`
+	IsText bool   `json:"is_text,omitempty"`
+}
+
+// ScanTree lists everything under root sorted by path, with checksums of regular files.
+func ScanTree(root string) ([]FileEntry, error) {
+	var entries []FileEntry
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		entry := FileEntry{Path: filepath.ToSlash(rel), Mode: info.Mode()}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if entry.Link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			entry.Size = info.Size()
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			sum := sha256.Sum256(data)
+			entry.SHA256 = hex.EncodeToString(sum[:])
+			if len(data) <= maxTextFileSize && utf8.Valid(data) && !bytes.ContainsRune(data, 0) {
+				entry.Text, entry.IsText = string(data), true
+			}
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	// WalkDir visits out/ before out.log, but '/' sorts after '.', and diffs merge by path
+	slices.SortFunc(entries, func(a, b FileEntry) int { return strings.Compare(a.Path, b.Path) })
+	return entries, err
+}
+
+// CopyTree copies src into dst, keeping modes and symlinks. skip leaves out files and
+// directories by their path relative to src. Special files like sockets are not copied.
+func CopyTree(src, dst string, skip func(rel string, d fs.DirEntry) bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if skip != nil && skip(rel, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return copyRegularFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyRegularFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// OpenFile applies the umask; the copy must keep the exact mode, since modes are diffed
+	return os.Chmod(dst, mode)
+}
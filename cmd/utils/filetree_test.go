@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestScanTreeSortsByPath(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"dir/inner", "dir.ext", "a"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ScanTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	if want := []string{"a", "dir", "dir.ext", "dir/inner"}; !slices.Equal(paths, want) {
+		t.Errorf("got %q, want %q", paths, want)
+	}
+}
+
+func TestCopyTreeSkipsAndKeepsModes(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, "cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "cache", "big"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CopyTree(src, dst, func(rel string, _ os.DirEntry) bool { return rel == "cache" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "run.sh"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("run.sh not copied executable: %v %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "cache")); !os.IsNotExist(err) {
+		t.Error("skipped directory was copied")
+	}
+}
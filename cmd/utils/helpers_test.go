@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTestRoot points the jfvm paths these tests touch at a fresh directory.
+func useTestRoot(t *testing.T) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "."+ToolName)
+	paths := map[*string]string{
+		&JfvmRoot:            root,
+		&JfvmConfig:          filepath.Join(root, ConfigFile),
+		&JfvmVersions:        filepath.Join(root, VersionsDir),
+		&JfvmAliases:         filepath.Join(root, AliasesDir),
+		&JfvmAliasMeta:       filepath.Join(root, AliasMetaDir),
+		&JfvmShim:            filepath.Join(root, ShimDir),
+		&JfvmJournal:         filepath.Join(root, JournalFile),
+		&JfvmProjects:        filepath.Join(root, ProjectsFile),
+		&JfvmTrash:           filepath.Join(root, TrashDir),
+		&JfvmCompareProfiles: filepath.Join(root, ProfilesFile),
+		&JfvmRecordings:      filepath.Join(root, RecordingsDir),
+	}
+	for path, value := range paths {
+		previous := *path
+		*path = value
+		t.Cleanup(func() { *path = previous })
+	}
+	t.Chdir(t.TempDir())
+}
+
+// installTestBinary writes content as the jf binary of version.
+func installTestBinary(t *testing.T, version, content string) string {
+	t.Helper()
+	dir := filepath.Join(JfvmVersions, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, BinaryName)
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
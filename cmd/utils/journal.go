@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxJournalEntries bounds the switch journal like the usage history.
+const maxJournalEntries = 200
+
+// SwitchEntry records one change of the active version and what caused it.
+type SwitchEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Source    string    `json:"source,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// GetCurrentVersion returns the active version from the config file, or "" if none is set.
+func GetCurrentVersion() string {
+	data, err := os.ReadFile(JfvmConfig)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func LoadJournal() ([]SwitchEntry, error) {
+	data, err := os.ReadFile(JfvmJournal)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []SwitchEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func saveJournal(entries []SwitchEntry) error {
+	if err := os.MkdirAll(filepath.Dir(JfvmJournal), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(JfvmJournal, data, 0644)
+}
+
+// SetActiveVersion writes the active version to the config file and records the switch
+// in the journal. Source describes what requested the switch (an alias, .jfrog-version, ...)
+// and reason is an optional note from the user.
+func SetActiveVersion(version, source, reason string) error {
+	previous := GetCurrentVersion()
+	if err := os.MkdirAll(JfvmRoot, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(JfvmConfig, []byte(version), 0644); err != nil {
+		return err
+	}
+	if previous == version {
+		return nil
+	}
+
+	// A broken journal must never prevent switching versions, so start a new one
+	entries, _ := LoadJournal()
+	entries = append(entries, SwitchEntry{
+		Timestamp: time.Now(),
+		From:      previous,
+		To:        version,
+		Source:    source,
+		Reason:    reason,
+	})
+	if len(entries) > maxJournalEntries {
+		entries = entries[len(entries)-maxJournalEntries:]
+	}
+	return saveJournal(entries)
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSetActiveVersionJournalsOnlyChanges(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "2.70.0", "2.71.0"} {
+		if err := SetActiveVersion(version, "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := LoadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("journal has %d entries, want 2: %+v", len(entries), entries)
+	}
+	if entries[0].From != "" || entries[0].To != "2.70.0" || entries[1].From != "2.70.0" || entries[1].To != "2.71.0" {
+		t.Errorf("got %+v", entries)
+	}
+}
+
+func TestSetActiveVersionSurvivesBrokenJournal(t *testing.T) {
+	useTestRoot(t)
+	if err := os.MkdirAll(JfvmRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(JfvmJournal, []byte("[{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetActiveVersion("2.70.0", "test", "fresh start"); err != nil {
+		t.Fatal(err)
+	}
+	if GetCurrentVersion() != "2.70.0" {
+		t.Errorf("active version is %q", GetCurrentVersion())
+	}
+	entries, err := LoadJournal()
+	if err != nil || len(entries) != 1 || entries[0].Reason != "fresh start" {
+		t.Errorf("got %+v, %v", entries, err)
+	}
+}
+
+func TestJournalIsBounded(t *testing.T) {
+	useTestRoot(t)
+	for i := 0; i < maxJournalEntries+5; i++ {
+		if err := SetActiveVersion(fmt.Sprintf("2.%d.0", i), "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := LoadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxJournalEntries || entries[0].To != "2.5.0" {
+		t.Errorf("journal has %d entries starting at %s", len(entries), entries[0].To)
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ManifestFile = ".jfvm.yaml"
+
+// PluginRequirement is a jf plugin a project needs, optionally pinned to a version.
+type PluginRequirement struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// ProjectManifest is the team-shared .jfvm.yaml checked into a repository. Checksums map
+// versions to SHA-256 digests of the jf binary per platform (e.g. linux-amd64, mac-arm64).
+type ProjectManifest struct {
+	Version   string                       `yaml:"version,omitempty"`
+	Aliases   map[string]string            `yaml:"aliases,omitempty"`
+	Checksums map[string]map[string]string `yaml:"checksums,omitempty"`
+	Plugins   []PluginRequirement          `yaml:"plugins,omitempty"`
+
+	// Path is the manifest file the values were read from
+	Path string `yaml:"-"`
+}
+
+// FindProjectManifest looks for .jfvm.yaml in the current directory and its parents.
+// It returns nil without an error when the current directory is not inside a project.
+func FindProjectManifest() (*ProjectManifest, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ManifestFile)
+		if _, err := os.Stat(path); err == nil {
+			return LoadProjectManifest(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func LoadProjectManifest(path string) (*ProjectManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &ProjectManifest{Path: path}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if manifest.Version != "" && !IsReleaseVersion(manifest.Version) {
+		if _, err := ParseVersionRange(manifest.Version); err != nil {
+			return nil, fmt.Errorf("%s: version must be a version or a range: %w", path, err)
+		}
+	}
+	for name, version := range manifest.Aliases {
+		if err := ValidateAliasName(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if version == "" {
+			return nil, fmt.Errorf("%s: alias '%s' has no version", path, name)
+		}
+	}
+	for _, plugin := range manifest.Plugins {
+		if plugin.Name == "" {
+			return nil, fmt.Errorf("%s: plugin entries need a name", path)
+		}
+	}
+	return manifest, nil
+}
+
+// AliasNames returns the project alias names, sorted.
+func (m *ProjectManifest) AliasNames() []string {
+	names := make([]string, 0, len(m.Aliases))
+	for name := range m.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveProjectAlias looks an alias up in the manifest of the enclosing project, if any.
+func resolveProjectAlias(name string) (string, bool) {
+	manifest, err := FindProjectManifest()
+	if err != nil || manifest == nil {
+		return "", false
+	}
+	version, ok := manifest.Aliases[name]
+	return version, ok
+}
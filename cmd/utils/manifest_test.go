@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectManifestValidates(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		yaml, err string
+	}{
+		{"version: 2.74.0\naliases:\n  ci: 2.73.0\nplugins:\n  - name: rt-fs\n    version: 1.0.0\n", ""},
+		{"version: ^2.74\n", ""},
+		{"version: soon\n", "version must be a version or a range"},
+		{"aliases:\n  latest: 2.74.0\n", "reserved keyword"},
+		{"aliases:\n  ci: \"\"\n", "alias 'ci' has no version"},
+		{"plugins:\n  - version: 1.0.0\n", "plugin entries need a name"},
+		{"version: [2.74.0\n", "failed to parse"},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, ManifestFile)
+		if err := os.WriteFile(path, []byte(test.yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadProjectManifest(path)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%q: %v", test.yaml, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%q: got %v, want %q", test.yaml, err, test.err)
+		}
+	}
+}
+
+func TestProjectAliasesOverridePersonalOnes(t *testing.T) {
+	useTestRoot(t)
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ManifestFile), []byte("aliases:\n  ci: 2.73.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(project, "sub", "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAlias("ci", "2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, _ := ResolveAlias("ci"); version != "2.70.0" {
+		t.Errorf("outside the project ci is %s, want 2.70.0", version)
+	}
+	t.Chdir(filepath.Join(project, "sub", "dir"))
+	manifest, err := FindProjectManifest()
+	if err != nil || manifest == nil {
+		t.Fatalf("manifest not found from a subdirectory: %v", err)
+	}
+	if version, _ := ResolveAlias("ci"); version != "2.73.0" {
+		t.Errorf("inside the project ci is %s, want 2.73.0", version)
+	}
+	if version, _ := ResolvePersonalAlias("ci"); version != "2.70.0" {
+		t.Errorf("personal ci is %s, want 2.70.0", version)
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const MetadataFile = "metadata.json"
+
+// Ways a version that was not downloaded got into jfvm.
+const (
+	SourceLink  = "link"
+	SourceBuild = "build"
+	SourceGit   = "source"
+)
+
+// VersionMetadata describes where a linked or locally built version came from.
+// Downloaded releases have none.
+type VersionMetadata struct {
+	Source     string    `json:"source"`
+	From       string    `json:"from,omitempty"`
+	Symlink    bool      `json:"symlink,omitempty"`
+	Repo       string    `json:"repo,omitempty"`
+	GitRef     string    `json:"git_ref,omitempty"`
+	GitCommit  string    `json:"git_commit,omitempty"`
+	GitDirty   bool      `json:"git_dirty,omitempty"`
+	CLIVersion string    `json:"cli_version,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LoadVersionMetadata returns the metadata of a version, or nil if it has none.
+func LoadVersionMetadata(version string) (*VersionMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(JfvmVersions, version, MetadataFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var meta VersionMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func SaveVersionMetadata(version string, meta VersionMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(JfvmVersions, version, MetadataFile), data, 0644)
+}
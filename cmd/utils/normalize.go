@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// replaceSeparator separates the pattern from the replacement in replace rules.
+const replaceSeparator = "=>"
+
+type replacement struct {
+	pattern *regexp.Regexp
+	with    string
+}
+
+// builtinNormalizers mask values that change on every run. Order matters: full timestamps
+// must be replaced before their time part would be taken for something else.
+var builtinNormalizers = map[string][]replacement{
+	"timestamps": {
+		{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<TIMESTAMP>"},
+		{regexp.MustCompile(`\b\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\b`), "<TIMESTAMP>"},
+		{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b`), "<TIME>"},
+	},
+	"uuids": {
+		{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<UUID>"},
+	},
+	"ids": {
+		{regexp.MustCompile(`(?i)\b[0-9a-f]{16,}\b`), "<ID>"},
+	},
+	"paths": tempPathNormalizers(),
+	"durations": {
+		{regexp.MustCompile(`\b(\d+(\.\d+)?(ns|µs|us|ms|s|m|h))+\b`), "<DURATION>"},
+	},
+}
+
+// builtinOrder is the order builtins are applied in, and what "all" expands to.
+var builtinOrder = []string{"timestamps", "uuids", "ids", "paths", "durations"}
+
+func tempPathNormalizers() []replacement {
+	rules := []replacement{
+		{regexp.MustCompile(`(/private)?/var/folders/[^\s"']+`), "<TMP>"},
+		{regexp.MustCompile(`/tmp/[^\s"']+`), "<TMP>"},
+		{regexp.MustCompile(`(?i)[A-Z]:\\Users\\[^\\\s]+\\AppData\\Local\\Temp\\[^\s"']+`), "<TMP>"},
+	}
+	if tmp := strings.TrimRight(os.TempDir(), `/\`); tmp != "" && tmp != "/tmp" {
+		rules = append(rules, replacement{regexp.MustCompile(regexp.QuoteMeta(tmp) + `[/\\][^\s"']+`), "<TMP>"})
+	}
+	if HomeDir != "" {
+		rules = append(rules, replacement{regexp.MustCompile(regexp.QuoteMeta(HomeDir)), "<HOME>"})
+	}
+	return rules
+}
+
+// BuiltinNormalizerNames lists the built-in normalizers in the order they are applied.
+func BuiltinNormalizerNames() []string {
+	return slices.Clone(builtinOrder)
+}
+
+// NormalizationRules describes how outputs are normalized before they are compared.
+type NormalizationRules struct {
+	// Builtins are names of built-in normalizers, or "all"
+	Builtins []string `json:"builtins,omitempty" yaml:"normalize,omitempty"`
+	// Replace rules have the form <regex>=><replacement>
+	Replace []string `json:"replace,omitempty" yaml:"replace,omitempty"`
+	// IgnoreLines drops every line matching one of these regexes
+	IgnoreLines []string `json:"ignore_lines,omitempty" yaml:"ignore_lines,omitempty"`
+}
+
+// Merge returns the union of both rule sets.
+func (r NormalizationRules) Merge(other NormalizationRules) NormalizationRules {
+	return NormalizationRules{
+		Builtins:    append(slices.Clone(r.Builtins), other.Builtins...),
+		Replace:     append(slices.Clone(r.Replace), other.Replace...),
+		IgnoreLines: append(slices.Clone(r.IgnoreLines), other.IgnoreLines...),
+	}
+}
+
+func (r NormalizationRules) IsEmpty() bool {
+	return len(r.Builtins) == 0 && len(r.Replace) == 0 && len(r.IgnoreLines) == 0
+}
+
+func (r NormalizationRules) String() string {
+	var parts []string
+	if len(r.Builtins) > 0 {
+		parts = append(parts, strings.Join(r.Builtins, ", "))
+	}
+	if len(r.Replace) > 0 {
+		parts = append(parts, fmt.Sprintf("%d replace rules", len(r.Replace)))
+	}
+	if len(r.IgnoreLines) > 0 {
+		parts = append(parts, fmt.Sprintf("%d ignored line patterns", len(r.IgnoreLines)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Normalizer applies compiled normalization rules to command output.
+type Normalizer struct {
+	replacements []replacement
+	ignore       []*regexp.Regexp
+}
+
+// Compile validates the rules and prepares them for Apply.
+func (r NormalizationRules) Compile() (*Normalizer, error) {
+	n := &Normalizer{}
+
+	selected := make(map[string]bool)
+	for _, name := range r.Builtins {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			for _, builtin := range builtinOrder {
+				selected[builtin] = true
+			}
+			continue
+		}
+		if _, ok := builtinNormalizers[name]; !ok {
+			return nil, fmt.Errorf("unknown normalizer '%s' (available: all, %s)", name, strings.Join(builtinOrder, ", "))
+		}
+		selected[name] = true
+	}
+	for _, name := range builtinOrder {
+		if selected[name] {
+			n.replacements = append(n.replacements, builtinNormalizers[name]...)
+		}
+	}
+
+	for _, rule := range r.Replace {
+		pattern, with, found := strings.Cut(rule, replaceSeparator)
+		if !found {
+			return nil, fmt.Errorf("invalid replace rule '%s': expected <regex>%s<replacement>", rule, replaceSeparator)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid replace rule '%s': %w", rule, err)
+		}
+		n.replacements = append(n.replacements, replacement{re, with})
+	}
+
+	for _, pattern := range r.IgnoreLines {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore-lines pattern '%s': %w", pattern, err)
+		}
+		n.ignore = append(n.ignore, re)
+	}
+	return n, nil
+}
+
+// Apply drops ignored lines, then applies the replacements.
+func (n *Normalizer) Apply(text string) string {
+	if n == nil || text == "" {
+		return text
+	}
+	if len(n.ignore) > 0 {
+		var kept []string
+		for _, line := range strings.Split(text, "\n") {
+			ignored := false
+			for _, re := range n.ignore {
+				if re.MatchString(line) {
+					ignored = true
+					break
+				}
+			}
+			if !ignored {
+				kept = append(kept, line)
+			}
+		}
+		text = strings.Join(kept, "\n")
+	}
+	for _, r := range n.replacements {
+		text = r.pattern.ReplaceAllString(text, r.with)
+	}
+	return text
+}
+
+// LoadCompareProfiles returns the named normalization profiles saved in the jfvm root.
+func LoadCompareProfiles() (map[string]NormalizationRules, error) {
+	profiles := make(map[string]NormalizationRules)
+	data, err := os.ReadFile(JfvmCompareProfiles)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", JfvmCompareProfiles, err)
+	}
+	return profiles, nil
+}
+
+// LoadCompareProfile returns one named normalization profile.
+func LoadCompareProfile(name string) (NormalizationRules, error) {
+	profiles, err := LoadCompareProfiles()
+	if err != nil {
+		return NormalizationRules{}, err
+	}
+	rules, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for profile := range profiles {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return rules, fmt.Errorf("normalization profile '%s' not found (no profiles saved yet)", name)
+		}
+		return rules, fmt.Errorf("normalization profile '%s' not found (available: %s)", name, strings.Join(names, ", "))
+	}
+	return rules, nil
+}
+
+// SaveCompareProfile stores rules under name, replacing an existing profile.
+func SaveCompareProfile(name string, rules NormalizationRules) error {
+	if _, err := rules.Compile(); err != nil {
+		return err
+	}
+	profiles, err := LoadCompareProfiles()
+	if err != nil {
+		return err
+	}
+	profiles[name] = rules
+
+	// Without HTML escaping the "=>" of replace rules stays readable for hand edits
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(profiles); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(JfvmRoot, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(JfvmCompareProfiles, buf.Bytes(), 0644)
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinNormalizers(t *testing.T) {
+	tests := []struct {
+		builtin, input, want string
+	}{
+		{"timestamps", "at 2025-03-01T10:20:30.123Z done", "at <TIMESTAMP> done"},
+		{"timestamps", "at 2025-03-01 10:20:30+02:00", "at <TIMESTAMP>"},
+		{"timestamps", "[Info] 2025/03/01 10:20:30 uploading", "[Info] <TIMESTAMP> uploading"},
+		{"timestamps", "took until 10:20:30.5", "took until <TIME>"},
+		{"uuids", "id 3F2504E0-4F89-11D3-9A0C-0305E82C3301", "id <UUID>"},
+		{"ids", "sha 0123456789abcdef0123 and v2.74.0", "sha <ID> and v2.74.0"},
+		{"ids", "short cafe1234", "short cafe1234"},
+		{"paths", "wrote /tmp/jfrog.123/file.txt", "wrote <TMP>"},
+		{"paths", `"/private/var/folders/x1/T/a"`, `"<TMP>"`},
+		{"durations", "took 1m30.5s and 250ms", "took <DURATION> and <DURATION>"},
+	}
+	for _, test := range tests {
+		normalizer, err := NormalizationRules{Builtins: []string{test.builtin}}.Compile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := normalizer.Apply(test.input); got != test.want {
+			t.Errorf("%s(%q) = %q, want %q", test.builtin, test.input, got, test.want)
+		}
+	}
+}
+
+func TestNormalizerAllAppliesTimestampsFirst(t *testing.T) {
+	normalizer, err := NormalizationRules{Builtins: []string{" ALL "}}.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The time part of a full timestamp must not be left for the <TIME> rule
+	if got := normalizer.Apply("2025-03-01T10:20:30Z in 2s"); got != "<TIMESTAMP> in <DURATION>" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNormalizerReplaceAndIgnoreLines(t *testing.T) {
+	normalizer, err := NormalizationRules{
+		Replace:     []string{`build-(\d+)=>build-N`, `x{1,3}=>X`},
+		IgnoreLines: []string{`^\[Debug\]`},
+	}.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "[Debug] resolving build-42\nbuild-42 xxxx\n[Info] done"
+	if got, want := normalizer.Apply(input), "build-N XX\n[Info] done"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var nothing *Normalizer
+	if got := nothing.Apply("as is"); got != "as is" {
+		t.Errorf("a nil normalizer changed the text to %q", got)
+	}
+}
+
+func TestCompileRejectsInvalidRules(t *testing.T) {
+	for _, test := range []struct {
+		rules NormalizationRules
+		err   string
+	}{
+		{NormalizationRules{Builtins: []string{"dates"}}, "unknown normalizer 'dates' (available: all, timestamps, uuids, ids, paths, durations)"},
+		{NormalizationRules{Replace: []string{"no separator"}}, "invalid replace rule 'no separator': expected <regex>=><replacement>"},
+		{NormalizationRules{Replace: []string{"a(=>b"}}, "invalid replace rule 'a(=>b'"},
+		{NormalizationRules{IgnoreLines: []string{"[unclosed"}}, "invalid ignore-lines pattern '[unclosed'"},
+	} {
+		if _, err := test.rules.Compile(); err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%+v: got %v, want %q", test.rules, err, test.err)
+		}
+	}
+}
+
+func TestCompareProfiles(t *testing.T) {
+	useTestRoot(t)
+	if _, err := LoadCompareProfile("ci"); err == nil || err.Error() != "normalization profile 'ci' not found (no profiles saved yet)" {
+		t.Errorf("got %v", err)
+	}
+
+	rules := NormalizationRules{Builtins: []string{"timestamps"}, Replace: []string{"a=>b"}}
+	if err := SaveCompareProfile("ci", rules); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCompareProfile("local", NormalizationRules{IgnoreLines: []string{"^#"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCompareProfile("broken", NormalizationRules{Builtins: []string{"dates"}}); err == nil {
+		t.Error("a profile with an unknown normalizer was saved")
+	}
+
+	loaded, err := LoadCompareProfile("ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != "timestamps, 1 replace rules" {
+		t.Errorf("loaded %q", loaded)
+	}
+	if _, err := LoadCompareProfile("ci2"); err == nil || err.Error() != "normalization profile 'ci2' not found (available: ci, local)" {
+		t.Errorf("got %v", err)
+	}
+
+	merged := loaded.Merge(NormalizationRules{Builtins: []string{"uuids"}, IgnoreLines: []string{"^#"}})
+	if merged.String() != "timestamps, uuids, 1 replace rules, 1 ignored line patterns" || len(loaded.Builtins) != 1 {
+		t.Errorf("merged %q from %q", merged, loaded)
+	}
+}
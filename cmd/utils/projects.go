@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RecordProjectFile remembers a .jfrog-version or .jfvm.yaml file that selected a version,
+// so cleanup commands can keep the versions that projects on this machine rely on.
+// Failures are ignored: the registry is only a hint.
+func RecordProjectFile(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	known := KnownProjectFiles()
+	for _, existing := range known {
+		if existing == abs {
+			return
+		}
+	}
+	known = append(known, abs)
+	sort.Strings(known)
+
+	data, err := json.MarshalIndent(known, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(JfvmRoot, 0755)
+	_ = os.WriteFile(JfvmProjects, data, 0644)
+}
+
+// KnownProjectFiles returns the recorded project files that still exist.
+func KnownProjectFiles() []string {
+	data, err := os.ReadFile(JfvmProjects)
+	if err != nil {
+		return nil
+	}
+	var recorded []string
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil
+	}
+
+	var existing []string
+	for _, path := range recorded {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+// ProjectFileVersions returns the versions a project file refers to. Aliases are resolved,
+// and ranges select the highest installed version that matches.
+func ProjectFileVersions(path string) []string {
+	var requested []string
+	if filepath.Base(path) == ManifestFile {
+		manifest, err := LoadProjectManifest(path)
+		if err != nil {
+			return nil
+		}
+		if manifest.Version != "" {
+			requested = append(requested, manifest.Version)
+		}
+		for _, name := range manifest.AliasNames() {
+			requested = append(requested, manifest.Aliases[name])
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		requested = append(requested, strings.TrimSpace(string(data)))
+	}
+
+	installed, _ := ListInstalledVersions()
+	var versions []string
+	for _, version := range requested {
+		if version == "" {
+			continue
+		}
+		if resolved, err := ResolvePersonalAlias(version); err == nil {
+			version = resolved
+		}
+		if !IsReleaseVersion(version) && IsVersionRange(version) {
+			if r, err := ParseVersionRange(version); err == nil {
+				if match, ok := r.HighestMatching(installed); ok {
+					version = match
+				}
+			}
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
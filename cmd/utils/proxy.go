@@ -0,0 +1,460 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Notes of calls the proxy answered itself.
+const (
+	CallNotRecorded = "not recorded"
+	CallFailed      = "failed"
+)
+
+// hopHeaders only apply to one connection and are never forwarded or recorded.
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// ProxyCA is a throwaway certificate authority that lets the proxy intercept HTTPS. jf
+// trusts it through the certs directory of an isolated home; SSL_CERT_FILE would not do,
+// since Go ignores it on macOS.
+type ProxyCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	leafKey *ecdsa.PrivateKey
+	mu      sync.Mutex
+	leaves  map[string]*tls.Certificate
+}
+
+// NewProxyCA creates a certificate authority valid for one day.
+func NewProxyCA() (*ProxyCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: ToolName + " recording proxy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyCA{
+		cert:    cert,
+		key:     key,
+		leafKey: leafKey,
+		leaves:  make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// PEM returns the CA certificate in PEM encoding.
+func (ca *ProxyCA) PEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
+// InstallIn adds the CA to the trusted certificates of a JFrog CLI home.
+func (ca *ProxyCA) InstallIn(cliHome string) error {
+	dir := filepath.Join(cliHome, "security", "certs")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "jfvm-proxy-ca.pem"), ca.PEM(), 0600)
+}
+
+// leaf returns a certificate for host signed by the CA.
+func (ca *ProxyCA) leaf(host string) (*tls.Certificate, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if cert, ok := ca.leaves[host]; ok {
+		return cert, nil
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     ca.cert.NotAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &ca.leafKey.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	cert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: ca.leafKey}
+	ca.leaves[host] = cert
+	return cert, nil
+}
+
+// HTTPReplay makes jf runs see the same server responses. While recording, runs reach the
+// real servers and their exchanges are recorded; once saved, runs are answered from the
+// recording alone, so they work offline.
+type HTTPReplay struct {
+	ca        *ProxyCA
+	mu        sync.Mutex
+	recording *Recording
+	recorder  bool
+}
+
+// NewHTTPReplay replays the recording called name, or records it if there is none yet or
+// rerecord is set.
+func NewHTTPReplay(name string, ca *ProxyCA, rerecord bool) (*HTTPReplay, error) {
+	if err := ValidateRecordingName(name); err != nil {
+		return nil, err
+	}
+	if !rerecord {
+		recording, err := LoadRecording(name)
+		if err == nil {
+			return &HTTPReplay{ca: ca, recording: recording}, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return &HTTPReplay{ca: ca, recording: &Recording{Name: name}, recorder: true}, nil
+}
+
+// Recording reports whether runs still record instead of replay.
+func (r *HTTPReplay) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recorder
+}
+
+// Name returns the name of the recording.
+func (r *HTTPReplay) Name() string {
+	return r.recording.Name
+}
+
+// Exchanges returns the number of recorded exchanges.
+func (r *HTTPReplay) Exchanges() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.recording.Exchanges)
+}
+
+// Save stores what was recorded with version; later runs replay it.
+func (r *HTTPReplay) Save(version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recording.Version = version
+	r.recording.RecordedAt = time.Now()
+	if err := r.recording.Save(); err != nil {
+		return fmt.Errorf("failed to save recording %s: %w", r.recording.Name, err)
+	}
+	r.recorder = false
+	return nil
+}
+
+// Start starts a proxy for one jf run. Every session replays from the start of the recording.
+func (r *HTTPReplay) Start() (*ProxySession, error) {
+	session := &ProxySession{ca: r.ca}
+	if r.Recording() {
+		session.transport = forwardTransport()
+		session.record = func(exchange Exchange) {
+			r.mu.Lock()
+			r.recording.Exchanges = append(r.recording.Exchanges, exchange)
+			r.mu.Unlock()
+		}
+	} else {
+		session.replay = newReplayer(r.recording)
+	}
+	return startSession(session)
+}
+
+// StartProxy starts a proxy for one jf run that only forwards requests to the servers, to
+// log the calls the run makes.
+func StartProxy(ca *ProxyCA) (*ProxySession, error) {
+	return startSession(&ProxySession{ca: ca, transport: forwardTransport()})
+}
+
+func forwardTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Bodies are passed on and recorded exactly as the server sent them, compressed or not
+	transport.DisableCompression = true
+	return transport
+}
+
+func startSession(session *ProxySession) (*ProxySession, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start proxy: %w", err)
+	}
+	session.listener = listener
+	session.server = &http.Server{Handler: session, ReadHeaderTimeout: 30 * time.Second}
+	go func() { _ = session.server.Serve(listener) }()
+	return session, nil
+}
+
+// HTTPCall is one request a jf run sent through the proxy.
+type HTTPCall struct {
+	Method string `json:"method"`
+	Host   string `json:"host"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Status int    `json:"status"`
+	// Note marks responses the proxy made up itself, like CallNotRecorded
+	Note string `json:"note,omitempty"`
+}
+
+// String renders a call as one line, like "GET example.jfrog.io/api/system/ping → 200".
+func (c HTTPCall) String() string {
+	target := c.Host + c.Path
+	if c.Query != "" {
+		target += "?" + c.Query
+	}
+	line := fmt.Sprintf("%s %s → %d", c.Method, target, c.Status)
+	if c.Note != "" {
+		line += " (" + c.Note + ")"
+	}
+	return line
+}
+
+// ProxySession is the proxy of one jf run.
+type ProxySession struct {
+	ca        *ProxyCA
+	listener  net.Listener
+	server    *http.Server
+	transport *http.Transport
+	record    func(Exchange)
+	replay    *replayer
+
+	mu    sync.Mutex
+	calls []HTTPCall
+}
+
+// Env returns the environment that routes a jf run through the proxy.
+func (s *ProxySession) Env() []string {
+	proxyURL := "http://" + s.listener.Addr().String()
+	return []string{
+		"HTTP_PROXY=" + proxyURL,
+		"HTTPS_PROXY=" + proxyURL,
+		"http_proxy=" + proxyURL,
+		"https_proxy=" + proxyURL,
+		"NO_PROXY=",
+		"no_proxy=",
+	}
+}
+
+// Calls returns the requests of the run in the order they arrived.
+func (s *ProxySession) Calls() []HTTPCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// Misses returns the requests that had no recorded response.
+func (s *ProxySession) Misses() []HTTPCall {
+	return slices.DeleteFunc(s.Calls(), func(call HTTPCall) bool { return call.Note != CallNotRecorded })
+}
+
+// begin logs a call when its request arrives, so concurrent calls keep their order.
+func (s *ProxySession) begin(method string, target *url.URL) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, HTTPCall{Method: method, Host: target.Host, Path: target.Path, Query: target.RawQuery})
+	return len(s.calls) - 1
+}
+
+func (s *ProxySession) finish(call, status int, note string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[call].Status, s.calls[call].Note = status, note
+}
+
+// Close stops the proxy.
+func (s *ProxySession) Close() error {
+	if s.transport != nil {
+		s.transport.CloseIdleConnections()
+	}
+	return s.server.Close()
+}
+
+func (s *ProxySession) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodConnect {
+		s.intercept(w, req)
+		return
+	}
+	if !req.URL.IsAbs() {
+		http.Error(w, "jfvm: only proxy requests are served", http.StatusBadRequest)
+		return
+	}
+	s.exchange(w, req, req.URL)
+}
+
+// intercept terminates the TLS connection of a CONNECT request with a certificate of the
+// proxy CA, so the requests inside it can be recorded and replayed.
+func (s *ProxySession) intercept(w http.ResponseWriter, req *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "jfvm: connection cannot be intercepted", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	target := req.Host
+	hostname, port, err := net.SplitHostPort(target)
+	if err != nil {
+		hostname, port = target, "443"
+	}
+	if port == "443" {
+		target = hostname
+	}
+	tlsConn := tls.Server(conn, &tls.Config{
+		NextProtos: []string{"http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName != "" {
+				return s.ca.leaf(hello.ServerName)
+			}
+			return s.ca.leaf(hostname)
+		},
+	})
+	server := &http.Server{
+		ReadHeaderTimeout: 30 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestURL := &url.URL{Scheme: "https", Host: target, Path: r.URL.Path, RawPath: r.URL.RawPath, RawQuery: r.URL.RawQuery}
+			s.exchange(w, r, requestURL)
+		}),
+	}
+	// Serve returns once the single connection was accepted; the connection is served until jf closes it
+	_ = server.Serve(&singleConnListener{conn: tlsConn})
+}
+
+// exchange answers one request, from the recording or from the server.
+func (s *ProxySession) exchange(w http.ResponseWriter, req *http.Request, target *url.URL) {
+	call := s.begin(req.Method, target)
+	fail := func(status int, note, message string) {
+		s.finish(call, status, note)
+		http.Error(w, "jfvm: "+message, status)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		fail(http.StatusBadRequest, CallFailed, err.Error())
+		return
+	}
+
+	rawURL := target.String()
+	if s.replay != nil {
+		exchange, ok := s.replay.find(req.Method, rawURL, body)
+		if !ok {
+			fail(http.StatusBadGateway, CallNotRecorded, fmt.Sprintf("no recorded response for %s %s", req.Method, rawURL))
+			return
+		}
+		s.finish(call, exchange.Status, "")
+		writeExchange(w, exchange)
+		return
+	}
+
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		fail(http.StatusBadRequest, CallFailed, err.Error())
+		return
+	}
+	out.Header = req.Header.Clone()
+	removeHopHeaders(out.Header)
+	resp, err := s.transport.RoundTrip(out)
+	if err != nil {
+		fail(http.StatusBadGateway, CallFailed, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fail(http.StatusBadGateway, CallFailed, err.Error())
+		return
+	}
+
+	exchange := Exchange{
+		Method:     req.Method,
+		URL:        rawURL,
+		BodySHA256: bodySHA256(body),
+		Status:     resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       respBody,
+	}
+	removeHopHeaders(exchange.Header)
+	if s.record != nil {
+		s.record(exchange)
+	}
+	s.finish(call, exchange.Status, "")
+	writeExchange(w, exchange)
+}
+
+func writeExchange(w http.ResponseWriter, exchange Exchange) {
+	for name, values := range exchange.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(exchange.Status)
+	_, _ = w.Write(exchange.Body)
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
+
+// singleConnListener hands one connection to an http.Server.
+type singleConnListener struct {
+	conn net.Conn
+	once sync.Once
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	var conn net.Conn
+	l.once.Do(func() { conn = l.conn })
+	if conn == nil {
+		return nil, io.EOF
+	}
+	return conn, nil
+}
+
+func (l *singleConnListener) Close() error { return nil }
+
+func (l *singleConnListener) Addr() net.Addr { return l.conn.LocalAddr() }
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// proxyClient returns a client that sends every request through session, trusting ca.
+func proxyClient(t *testing.T, session *ProxySession, ca *ProxyCA) *http.Client {
+	t.Helper()
+	proxyURL, err := url.Parse("http://" + session.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	if ca != nil {
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(ca.PEM())
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+	t.Cleanup(transport.CloseIdleConnections)
+	return &http.Client{Transport: transport}
+}
+
+// fetch sends a request and returns the status and body of the response.
+func fetch(t *testing.T, client *http.Client, method, target, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestHTTPReplayRecordsThenReplays(t *testing.T) {
+	useTestRoot(t)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Artifactory-Id", "test")
+		io.WriteString(w, r.Method+" "+r.URL.Path+" "+string(body))
+	}))
+	defer server.Close()
+
+	replay, err := NewHTTPReplay("smoke/ping", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replay.Recording() {
+		t.Fatal("a new recording replays")
+	}
+	session, err := replay.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := proxyClient(t, session, nil)
+	fetch(t, client, "GET", server.URL+"/api/system/ping", "")
+	fetch(t, client, "POST", server.URL+"/api/search/aql", "items.find()")
+	_ = session.Close()
+	if replay.Exchanges() != 2 || hits.Load() != 2 {
+		t.Fatalf("recorded %d exchanges with %d server hits, want 2", replay.Exchanges(), hits.Load())
+	}
+	if err := replay.Save("2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+	if replay.Recording() {
+		t.Error("still recording after saving")
+	}
+
+	// A later run replays the saved recording without reaching the server
+	replay, err = NewHTTPReplay("smoke/ping", nil, false)
+	if err != nil || replay.Recording() {
+		t.Fatalf("saved recording not loaded: %v", err)
+	}
+	session, err = replay.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	client = proxyClient(t, session, nil)
+	if status, body := fetch(t, client, "POST", server.URL+"/api/search/aql", "items.find()"); status != 200 || body != "POST /api/search/aql items.find()" {
+		t.Errorf("replayed %d %q", status, body)
+	}
+	if status, _ := fetch(t, client, "GET", server.URL+"/api/repositories", ""); status != http.StatusBadGateway {
+		t.Errorf("an unrecorded request got %d, want %d", status, http.StatusBadGateway)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("replaying reached the server %d times", hits.Load()-2)
+	}
+	misses := session.Misses()
+	if len(misses) != 1 || misses[0].Path != "/api/repositories" || misses[0].Note != CallNotRecorded {
+		t.Errorf("misses are %+v", misses)
+	}
+
+	// Re-recording starts over even though a recording exists
+	if replay, err := NewHTTPReplay("smoke/ping", nil, true); err != nil || !replay.Recording() || replay.Exchanges() != 0 {
+		t.Errorf("--rerecord did not start a new recording: %v", err)
+	}
+	if _, err := NewHTTPReplay("../ping", nil, false); err == nil {
+		t.Error("an unsafe recording name was accepted")
+	}
+}
+
+func TestProxyInterceptsHTTPS(t *testing.T) {
+	ca, err := NewProxyCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing listens at example.jfrog.io here: the response comes from the recording
+	replay := &HTTPReplay{ca: ca, recording: &Recording{Name: "https", Exchanges: []Exchange{
+		{Method: "GET", URL: "https://example.jfrog.io/artifactory/api/system/ping?verbose=1", Status: 200, Body: []byte("OK")},
+	}}}
+	session, err := replay.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	client := proxyClient(t, session, ca)
+	if status, body := fetch(t, client, "GET", "https://example.jfrog.io/artifactory/api/system/ping?verbose=1", ""); status != 200 || body != "OK" {
+		t.Errorf("got %d %q", status, body)
+	}
+	calls := session.Calls()
+	want := HTTPCall{Method: "GET", Host: "example.jfrog.io", Path: "/artifactory/api/system/ping", Query: "verbose=1", Status: 200}
+	if len(calls) != 1 || calls[0] != want {
+		t.Errorf("calls are %+v, want %+v", calls, want)
+	}
+
+	home := t.TempDir()
+	if err := ca.InstallIn(home); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(home, "security", "certs", "jfvm-proxy-ca.pem")); err != nil || string(data) != string(ca.PEM()) {
+		t.Errorf("CA not installed in the CLI home: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var recordingNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// Recording is the HTTP traffic of one jf run, replayed to other runs so they all see the
+// same server responses.
+type Recording struct {
+	Name       string     `json:"name"`
+	RecordedAt time.Time  `json:"recorded_at"`
+	Version    string     `json:"version"`
+	Exchanges  []Exchange `json:"exchanges"`
+}
+
+// Exchange is one recorded request and the response the server gave to it.
+type Exchange struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// BodySHA256 identifies the request body, so requests that only differ in their body,
+	// like two AQL queries, get their own responses
+	BodySHA256 string      `json:"body_sha256,omitempty"`
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// RecordingPath returns the file of a recording. Names may contain groups, like suite/case.
+func RecordingPath(name string) string {
+	return filepath.Join(JfvmRecordings, filepath.FromSlash(name)+".json")
+}
+
+// ValidateRecordingName rejects names that are not safe as paths below the recordings directory.
+func ValidateRecordingName(name string) error {
+	if !recordingNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid recording name '%s' (use letters, digits, '.', '_', '-' and '/' between groups)", name)
+	}
+	return nil
+}
+
+// LoadRecording reads a recording; the error satisfies os.IsNotExist when there is none.
+func LoadRecording(name string) (*Recording, error) {
+	data, err := os.ReadFile(RecordingPath(name))
+	if err != nil {
+		return nil, err
+	}
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", name, err)
+	}
+	return &recording, nil
+}
+
+// Save writes the recording to its file.
+func (r *Recording) Save() error {
+	path := RecordingPath(r.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Responses may contain tokens, so recordings are only readable by the user
+	return os.WriteFile(path, data, 0600)
+}
+
+func bodySHA256(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// replayer answers requests from a recording. Identical requests get the recorded responses
+// in the order they were recorded, and the last one once those run out. A request whose
+// body was not recorded falls back to the responses of the same method and URL.
+type replayer struct {
+	recording *Recording
+	mu        sync.Mutex
+	served    map[string]int
+}
+
+func newReplayer(recording *Recording) *replayer {
+	return &replayer{recording: recording, served: make(map[string]int)}
+}
+
+func (r *replayer) find(method, url string, body []byte) (Exchange, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sum := bodySHA256(body)
+	exact := func(e Exchange) bool { return e.Method == method && e.URL == url && e.BodySHA256 == sum }
+	loose := func(e Exchange) bool { return e.Method == method && e.URL == url }
+	for _, match := range []func(Exchange) bool{exact, loose} {
+		var candidates []Exchange
+		for _, exchange := range r.recording.Exchanges {
+			if match(exchange) {
+				candidates = append(candidates, exchange)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		key := strings.Join([]string{method, url, sum}, " ")
+		i := min(r.served[key], len(candidates)-1)
+		r.served[key]++
+		return candidates[i], true
+	}
+	return Exchange{}, false
+}
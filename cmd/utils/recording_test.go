@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateRecordingName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"smoke":           true,
+		"suite/case-1":    true,
+		"v2.74.0_ping":    true,
+		"":                false,
+		".hidden":         false,
+		"../escape":       false,
+		"suite/../escape": false,
+		"/absolute":       false,
+		"suite/":          false,
+		"with space":      false,
+		`windows\path`:    false,
+	} {
+		if err := ValidateRecordingName(name); (err == nil) != valid {
+			t.Errorf("%q: got %v, want valid %v", name, err, valid)
+		}
+	}
+}
+
+func TestRecordingSaveAndLoad(t *testing.T) {
+	useTestRoot(t)
+	if _, err := LoadRecording("suite/ping"); !os.IsNotExist(err) {
+		t.Fatalf("loading a missing recording: got %v, want a not-exist error", err)
+	}
+
+	recording := &Recording{Name: "suite/ping", Version: "2.70.0", Exchanges: []Exchange{
+		{Method: "GET", URL: "https://example.jfrog.io/artifactory/api/system/ping", Status: 200, Body: []byte("OK")},
+	}}
+	if err := recording.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(JfvmRecordings, "suite", "ping.json")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("recording is %v, want readable only by the user", info.Mode().Perm())
+	}
+
+	loaded, err := LoadRecording("suite/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != "2.70.0" || len(loaded.Exchanges) != 1 || string(loaded.Exchanges[0].Body) != "OK" {
+		t.Errorf("loaded %+v", loaded)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRecording("suite/ping"); err == nil || os.IsNotExist(err) {
+		t.Errorf("a corrupt recording: got %v", err)
+	}
+}
+
+func TestReplayerFind(t *testing.T) {
+	url := "https://example.jfrog.io/artifactory/api/search/aql"
+	query1, query2 := []byte(`items.find({"repo":"a"})`), []byte(`items.find({"repo":"b"})`)
+	replay := newReplayer(&Recording{Exchanges: []Exchange{
+		{Method: "GET", URL: url, Status: 503},
+		{Method: "GET", URL: url, Status: 200},
+		{Method: "POST", URL: url, BodySHA256: bodySHA256(query1), Status: 200, Body: []byte("a")},
+		{Method: "POST", URL: url, BodySHA256: bodySHA256(query2), Status: 200, Body: []byte("b")},
+	}})
+
+	// Identical requests get the recorded responses in order, then the last one again
+	for _, want := range []int{503, 200, 200} {
+		if exchange, ok := replay.find("GET", url, nil); !ok || exchange.Status != want {
+			t.Errorf("GET: got %d (%v), want %d", exchange.Status, ok, want)
+		}
+	}
+	if exchange, _ := replay.find("POST", url, query2); string(exchange.Body) != "b" {
+		t.Errorf("query b got the response %q", exchange.Body)
+	}
+	// A body that wasn't recorded falls back to the responses for the same method and URL
+	if exchange, ok := replay.find("POST", url, []byte("other")); !ok || string(exchange.Body) != "a" {
+		t.Errorf("unrecorded body got %q (%v), want the first POST response", exchange.Body, ok)
+	}
+	if _, ok := replay.find("DELETE", url, nil); ok {
+		t.Error("a request that was never recorded was answered")
+	}
+}
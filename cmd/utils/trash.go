@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	trashMetaFile    = "trash.json"
+	trashContentDir  = "version"
+	defaultTrashDays = 14
+)
+
+// TrashEntry is a removed version kept under ~/.jfvm/trash until it expires.
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Version   string    `json:"version"`
+	TrashedAt time.Time `json:"trashed_at"`
+	Size      int64     `json:"size"`
+	Linked    bool      `json:"linked"`
+}
+
+// TrashRetention is how long removed release versions are kept, configurable with
+// JFVM_TRASH_DAYS. Linked builds cannot be re-downloaded and never expire automatically.
+func TrashRetention() time.Duration {
+	days := defaultTrashDays
+	if value := os.Getenv("JFVM_TRASH_DAYS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Expired reports whether the entry is past the retention period.
+func (e TrashEntry) Expired() bool {
+	return !e.Linked && time.Since(e.TrashedAt) > TrashRetention()
+}
+
+// MoveToTrash moves an installed version into the trash and expires old entries.
+func MoveToTrash(version string) (TrashEntry, error) {
+	versionDir := filepath.Join(JfvmVersions, version)
+	size, _ := DirSize(versionDir)
+
+	entry := TrashEntry{
+		Version:   version,
+		TrashedAt: time.Now(),
+		Size:      size,
+		Linked:    !IsReleaseVersion(version),
+	}
+	entry.ID = fmt.Sprintf("%s@%d", version, entry.TrashedAt.UnixNano())
+	entryDir := filepath.Join(JfvmTrash, entry.ID)
+
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return entry, err
+	}
+	if err := writeTrashMeta(entryDir, entry); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return entry, err
+	}
+	if err := os.Rename(versionDir, filepath.Join(entryDir, trashContentDir)); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return entry, err
+	}
+
+	_, _ = ExpireTrash()
+	return entry, nil
+}
+
+func writeTrashMeta(entryDir string, entry TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(entryDir, trashMetaFile), data, 0644)
+}
+
+// ListTrash returns the trashed versions, newest first.
+func ListTrash() ([]TrashEntry, error) {
+	dirs, err := os.ReadDir(JfvmTrash)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TrashEntry
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(JfvmTrash, dir.Name(), trashMetaFile))
+		if err != nil {
+			continue
+		}
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entry.ID = dir.Name()
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TrashedAt.After(entries[j].TrashedAt)
+	})
+	return entries, nil
+}
+
+// RestoreFromTrash moves the most recently trashed copy of a version back into place.
+func RestoreFromTrash(version string) (TrashEntry, error) {
+	entries, err := ListTrash()
+	if err != nil {
+		return TrashEntry{}, err
+	}
+
+	for _, entry := range entries {
+		if entry.Version != version {
+			continue
+		}
+		versionDir := filepath.Join(JfvmVersions, version)
+		if _, err := os.Stat(versionDir); err == nil {
+			return entry, fmt.Errorf("version %s is installed; remove it before restoring", version)
+		}
+		if err := os.MkdirAll(JfvmVersions, 0755); err != nil {
+			return entry, err
+		}
+		entryDir := filepath.Join(JfvmTrash, entry.ID)
+		if err := os.Rename(filepath.Join(entryDir, trashContentDir), versionDir); err != nil {
+			return entry, err
+		}
+		return entry, os.RemoveAll(entryDir)
+	}
+	return TrashEntry{}, fmt.Errorf("version %s is not in the trash", version)
+}
+
+// DeleteFromTrash permanently deletes a trash entry.
+func DeleteFromTrash(entry TrashEntry) error {
+	return os.RemoveAll(filepath.Join(JfvmTrash, entry.ID))
+}
+
+// ExpireTrash permanently deletes expired entries and returns them.
+func ExpireTrash() ([]TrashEntry, error) {
+	entries, err := ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	var expired []TrashEntry
+	for _, entry := range entries {
+		if !entry.Expired() {
+			continue
+		}
+		if err := DeleteFromTrash(entry); err != nil {
+			return expired, err
+		}
+		expired = append(expired, entry)
+	}
+	return expired, nil
+}
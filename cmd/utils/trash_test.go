@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMoveToTrashAndRestore(t *testing.T) {
+	useTestRoot(t)
+	installTestBinary(t, "2.70.0", "first")
+	if _, err := MoveToTrash("2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+	installTestBinary(t, "2.70.0", "second")
+	if _, err := MoveToTrash("2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+	if CheckVersionExists("2.70.0") == nil {
+		t.Fatal("2.70.0 is still installed")
+	}
+
+	entries, err := ListTrash()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("got %+v, %v", entries, err)
+	}
+	if entries[0].Size != int64(len("second")) || entries[0].Linked {
+		t.Errorf("newest entry is %+v", entries[0])
+	}
+
+	// The most recent copy comes back first
+	if _, err := RestoreFromTrash("2.70.0"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(JfvmVersions, "2.70.0", BinaryName)); string(data) != "second" {
+		t.Errorf("restored %q, want the second copy", data)
+	}
+	if _, err := RestoreFromTrash("2.70.0"); err == nil || !strings.Contains(err.Error(), "remove it before restoring") {
+		t.Errorf("restoring over an installed version: got %v", err)
+	}
+	if _, err := RestoreFromTrash("2.99.0"); err == nil {
+		t.Error("restored a version that was never trashed")
+	}
+	if entries, _ := ListTrash(); len(entries) != 1 {
+		t.Errorf("trash holds %d entries, want 1", len(entries))
+	}
+}
+
+func TestExpireTrashKeepsLinkedBuilds(t *testing.T) {
+	useTestRoot(t)
+	t.Setenv("JFVM_TRASH_DAYS", "7")
+	for _, version := range []string{"2.70.0", "2.71.0", "my-build"} {
+		installTestBinary(t, version, version)
+		if _, err := MoveToTrash(version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Age two entries past the retention period
+	entries, _ := ListTrash()
+	for _, entry := range entries {
+		if entry.Version == "2.71.0" {
+			continue
+		}
+		entry.TrashedAt = time.Now().AddDate(0, 0, -8)
+		if err := writeTrashMeta(filepath.Join(JfvmTrash, entry.ID), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expired, err := ExpireTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].Version != "2.70.0" {
+		t.Errorf("expired %+v, want only 2.70.0", expired)
+	}
+	remaining, _ := ListTrash()
+	if len(remaining) != 2 {
+		t.Errorf("trash holds %+v, want 2.71.0 and my-build", remaining)
+	}
+}
+
+func TestTrashRetention(t *testing.T) {
+	for value, days := range map[string]int{"": 14, "3": 3, "0": 0, "-1": 14, "soon": 14} {
+		t.Setenv("JFVM_TRASH_DAYS", value)
+		if got := TrashRetention(); got != time.Duration(days)*24*time.Hour {
+			t.Errorf("JFVM_TRASH_DAYS=%q: got %s, want %d days", value, got, days)
+		}
+	}
+}
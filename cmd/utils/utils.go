@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,20 +14,40 @@ import (
 )
 
 const (
-	ToolName    = "jfvm"
-	ConfigFile  = "config"
-	VersionsDir = "versions"
-	BinaryName  = "jf"
-	ProjectFile = ".jfrog-version"
-	AliasesDir  = "aliases"
+	ToolName      = "jfvm"
+	ConfigFile    = "config"
+	VersionsDir   = "versions"
+	BinaryName    = "jf"
+	ProjectFile   = ".jfrog-version"
+	AliasesDir    = "aliases"
+	AliasMetaDir  = "alias-meta"
+	ShimDir       = "shim"
+	HistoryFile   = "history.json"
+	JournalFile   = "journal.json"
+	ProjectsFile  = "projects.json"
+	TrashDir      = "trash"
+	SourcesDir    = "sources"
+	ProfilesFile  = "compare-profiles.json"
+	SnapshotsDir  = "snapshots"
+	RecordingsDir = "recordings"
 )
 
 var (
-	HomeDir      = os.Getenv("HOME")
-	JfvmRoot     = filepath.Join(HomeDir, "."+ToolName)
-	JfvmConfig   = filepath.Join(JfvmRoot, ConfigFile)
-	JfvmVersions = filepath.Join(JfvmRoot, VersionsDir)
-	JfvmAliases  = filepath.Join(JfvmRoot, AliasesDir)
+	HomeDir             = os.Getenv("HOME")
+	JfvmRoot            = filepath.Join(HomeDir, "."+ToolName)
+	JfvmConfig          = filepath.Join(JfvmRoot, ConfigFile)
+	JfvmVersions        = filepath.Join(JfvmRoot, VersionsDir)
+	JfvmAliases         = filepath.Join(JfvmRoot, AliasesDir)
+	JfvmAliasMeta       = filepath.Join(JfvmRoot, AliasMetaDir)
+	JfvmShim            = filepath.Join(JfvmRoot, ShimDir)
+	JfvmHistory         = filepath.Join(JfvmRoot, HistoryFile)
+	JfvmJournal         = filepath.Join(JfvmRoot, JournalFile)
+	JfvmProjects        = filepath.Join(JfvmRoot, ProjectsFile)
+	JfvmTrash           = filepath.Join(JfvmRoot, TrashDir)
+	JfvmSources         = filepath.Join(JfvmRoot, SourcesDir)
+	JfvmCompareProfiles = filepath.Join(JfvmRoot, ProfilesFile)
+	JfvmSnapshots       = filepath.Join(JfvmRoot, SnapshotsDir)
+	JfvmRecordings      = filepath.Join(JfvmRoot, RecordingsDir)
 )
 
 func GetVersionFromProjectFile() (string, error) {
@@ -38,7 +62,23 @@ func GetVersionFromProjectFile() (string, error) {
 	return version, nil
 }
 
+// ResolveAlias resolves an alias, preferring the aliases declared in the .jfvm.yaml of the
+// enclosing project over personal ones
 func ResolveAlias(name string) (string, error) {
+	if !aliasNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid alias name '%s'", name)
+	}
+	if version, ok := resolveProjectAlias(name); ok {
+		return version, nil
+	}
+	return ResolvePersonalAlias(name)
+}
+
+// ResolvePersonalAlias resolves an alias from ~/.jfvm/aliases only
+func ResolvePersonalAlias(name string) (string, error) {
+	if !aliasNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid alias name '%s'", name)
+	}
 	path := filepath.Join(JfvmAliases, name)
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -77,6 +117,29 @@ func CheckVersionExists(version string) error {
 	return nil
 }
 
+// FileSHA256 returns the hex-encoded SHA-256 digest of a file
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// JfrogCliHome returns the configuration directory jf uses, honoring JFROG_CLI_HOME_DIR
+func JfrogCliHome() string {
+	if dir := os.Getenv("JFROG_CLI_HOME_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(HomeDir, ".jfrog")
+}
+
 // GetLatestVersion fetches the latest version from GitHub API
 func GetLatestVersion() (string, error) {
 	// Use GitHub API to get the latest release
@@ -114,3 +177,105 @@ func GetLatestVersion() (string, error) {
 
 	return version, nil
 }
+
+// GetAvailableVersions fetches all released JFrog CLI v2 versions from the GitHub API
+func GetAvailableVersions() ([]string, error) {
+	var versions []string
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/jfrog/jfrog-cli/releases?per_page=100&page=%d", page)
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch available versions: %w", err)
+		}
+
+		var releases []struct {
+			TagName    string `json:"tag_name"`
+			Draft      bool   `json:"draft"`
+			Prerelease bool   `json:"prerelease"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch available versions: HTTP %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&releases)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse releases: %w", err)
+		}
+
+		for _, release := range releases {
+			version := strings.TrimPrefix(release.TagName, "v")
+			if release.Draft || release.Prerelease || !strings.HasPrefix(version, "2.") || !IsReleaseVersion(version) {
+				continue
+			}
+			versions = append(versions, version)
+		}
+		if len(releases) < 100 {
+			break
+		}
+	}
+
+	SortVersions(versions)
+	return versions, nil
+}
+
+// ListInstalledVersions returns the names of all installed versions, sorted
+func ListInstalledVersions() ([]string, error) {
+	entries, err := os.ReadDir(JfvmVersions)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	SortVersions(versions)
+	return versions, nil
+}
+
+// DirSize returns the total size in bytes of the regular files below path
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// FormatBytes renders a byte count in human-readable units
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// Confirm asks a yes/no question on the terminal and defaults to no
+func Confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var releaseVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// IsReleaseVersion reports whether version looks like a published JFrog CLI release (e.g. 2.74.0)
+// as opposed to a linked local build or an alias.
+func IsReleaseVersion(version string) bool {
+	return releaseVersionPattern.MatchString(version)
+}
+
+// Version is a parsed major.minor.patch release version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or higher than other.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseVersion parses versions like 2.74.0, v2.74.0 or the partial forms 2.74 and 2,
+// in which the missing components are zero.
+func ParseVersion(s string) (Version, error) {
+	v, _, err := parsePartialVersion(s)
+	return v, err
+}
+
+// parsePartialVersion also reports how many components were given, which ranges need
+// to tell ~2 from ~2.0.
+func parsePartialVersion(s string) (Version, int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version: %s", s)
+	}
+
+	var nums [3]int
+	given := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version: %s", s)
+		}
+		nums[i] = n
+		given++
+	}
+	if given == 0 {
+		return Version{}, 0, fmt.Errorf("invalid version: %s", s)
+	}
+	return Version{nums[0], nums[1], nums[2]}, given, nil
+}
+
+// SortVersions sorts release versions in ascending order. Names that are not release
+// versions (linked builds) are placed first, alphabetically.
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := ParseVersion(versions[i])
+		vj, errJ := ParseVersion(versions[j])
+		switch {
+		case errI != nil && errJ != nil:
+			return versions[i] < versions[j]
+		case errI != nil:
+			return true
+		case errJ != nil:
+			return false
+		default:
+			return vi.Compare(vj) < 0
+		}
+	})
+}
+
+type versionConstraint struct {
+	op      string
+	version Version
+}
+
+// VersionRange is a set of constraints that must all hold, e.g. ">=2.70 <2.80" or "^2.74".
+type VersionRange struct {
+	spec        string
+	constraints []versionConstraint
+}
+
+func (r VersionRange) String() string {
+	return r.spec
+}
+
+// IsVersionRange reports whether spec is a range rather than a single version or name.
+func IsVersionRange(spec string) bool {
+	if IsReleaseVersion(spec) {
+		return false
+	}
+	_, err := ParseVersionRange(spec)
+	return err == nil
+}
+
+// ParseVersionRange parses npm-style ranges: exact versions (2.74.0), caret (^2.74),
+// tilde (~2.74.1), wildcards (2.74.x) and comparisons (>=2.70 <2.80), separated by
+// spaces or commas.
+func ParseVersionRange(spec string) (VersionRange, error) {
+	r := VersionRange{spec: spec}
+	fields := strings.FieldsFunc(spec, func(c rune) bool { return c == ' ' || c == ',' })
+	if len(fields) == 0 {
+		return r, fmt.Errorf("empty version range")
+	}
+
+	for _, field := range fields {
+		constraints, err := parseConstraint(field)
+		if err != nil {
+			return r, fmt.Errorf("invalid version range '%s': %w", spec, err)
+		}
+		r.constraints = append(r.constraints, constraints...)
+	}
+	return r, nil
+}
+
+func parseConstraint(field string) ([]versionConstraint, error) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(field, op) {
+			v, err := ParseVersion(field[len(op):])
+			if err != nil {
+				return nil, err
+			}
+			return []versionConstraint{{op, v}}, nil
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(field, "^"):
+		v, _, err := parsePartialVersion(field[1:])
+		if err != nil {
+			return nil, err
+		}
+		return []versionConstraint{{">=", v}, {"<", Version{v.Major + 1, 0, 0}}}, nil
+	case strings.HasPrefix(field, "~"):
+		v, given, err := parsePartialVersion(field[1:])
+		if err != nil {
+			return nil, err
+		}
+		if given == 1 {
+			return []versionConstraint{{">=", v}, {"<", Version{v.Major + 1, 0, 0}}}, nil
+		}
+		return []versionConstraint{{">=", v}, {"<", Version{v.Major, v.Minor + 1, 0}}}, nil
+	}
+
+	v, given, err := parsePartialVersion(field)
+	if err != nil {
+		return nil, err
+	}
+	switch given {
+	case 1:
+		return []versionConstraint{{">=", v}, {"<", Version{v.Major + 1, 0, 0}}}, nil
+	case 2:
+		return []versionConstraint{{">=", v}, {"<", Version{v.Major, v.Minor + 1, 0}}}, nil
+	default:
+		return []versionConstraint{{"=", v}}, nil
+	}
+}
+
+// Matches reports whether version satisfies every constraint of the range.
+// Versions that are not release versions never match.
+func (r VersionRange) Matches(version string) bool {
+	if !IsReleaseVersion(strings.TrimPrefix(version, "v")) {
+		return false
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	for _, c := range r.constraints {
+		cmp := v.Compare(c.version)
+		var ok bool
+		switch c.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HighestMatching returns the highest of the given versions that satisfies the range.
+func (r VersionRange) HighestMatching(versions []string) (string, bool) {
+	var best string
+	var bestVersion Version
+	for _, candidate := range versions {
+		if !r.Matches(candidate) {
+			continue
+		}
+		v, _ := ParseVersion(candidate)
+		if best == "" || v.Compare(bestVersion) > 0 {
+			best, bestVersion = strings.TrimPrefix(candidate, "v"), v
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestVersionRangeMatches(t *testing.T) {
+	tests := []struct {
+		spec  string
+		match []string
+		miss  []string
+	}{
+		{"^2.74", []string{"2.74.0", "2.99.9"}, []string{"2.73.9", "3.0.0"}},
+		{"~2.74.1", []string{"2.74.1", "2.74.9"}, []string{"2.74.0", "2.75.0"}},
+		{"~2", []string{"2.0.0", "2.99.0"}, []string{"3.0.0"}},
+		{"2.74.x", []string{"2.74.0", "2.74.5"}, []string{"2.75.0"}},
+		{">=2.70 <2.80", []string{"2.70.0", "2.79.9"}, []string{"2.69.9", "2.80.0"}},
+		{">=2.70,<2.71", []string{"2.70.3"}, []string{"2.71.0"}},
+		{"2.74.0", []string{"2.74.0"}, []string{"2.74.1"}},
+		{"^2", []string{"2.74.0"}, []string{"my-build", "2.74.0-rc1"}},
+	}
+	for _, test := range tests {
+		r, err := ParseVersionRange(test.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", test.spec, err)
+		}
+		for _, version := range test.match {
+			if !r.Matches(version) {
+				t.Errorf("%s should match %s", test.spec, version)
+			}
+		}
+		for _, version := range test.miss {
+			if r.Matches(version) {
+				t.Errorf("%s should not match %s", test.spec, version)
+			}
+		}
+	}
+}
+
+func TestParseVersionRangeRejectsGarbage(t *testing.T) {
+	for _, spec := range []string{"", "^", ">=two", "2.74.0.1", "~x"} {
+		if _, err := ParseVersionRange(spec); err == nil {
+			t.Errorf("%q was accepted", spec)
+		}
+	}
+	for spec, isRange := range map[string]bool{"2.74.0": false, "prod": false, "^2.74": true, "2.74": true} {
+		if IsVersionRange(spec) != isRange {
+			t.Errorf("IsVersionRange(%q) = %v", spec, !isRange)
+		}
+	}
+}
+
+func TestHighestMatchingAndSortVersions(t *testing.T) {
+	versions := []string{"2.9.0", "2.74.3", "my-build", "2.10.1", "2.74.10", "3.0.0"}
+	r, _ := ParseVersionRange("^2.10")
+	if best, ok := r.HighestMatching(versions); !ok || best != "2.74.10" {
+		t.Errorf("got %s, want 2.74.10", best)
+	}
+	SortVersions(versions)
+	if want := []string{"my-build", "2.9.0", "2.10.1", "2.74.3", "2.74.10", "3.0.0"}; !slices.Equal(versions, want) {
+		t.Errorf("got %v, want %v", versions, want)
+	}
+}
+
+func TestAliasMetaRefreshDue(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	tests := []struct {
+		meta AliasMeta
+		due  bool
+	}{
+		{AliasMeta{Track: "latest", RefreshInterval: "30m0s", LastRefresh: hourAgo}, true},
+		{AliasMeta{Track: "latest", RefreshInterval: "2h0m0s", LastRefresh: hourAgo}, false},
+		{AliasMeta{Track: "latest", RefreshInterval: "30m0s", LastRefresh: hourAgo, Held: true}, false},
+		{AliasMeta{Track: "latest", LastRefresh: hourAgo}, false},
+		{AliasMeta{RefreshInterval: "30m0s", LastRefresh: hourAgo}, false},
+	}
+	for i, test := range tests {
+		if got := test.meta.RefreshDue(); got != test.due {
+			t.Errorf("case %d: got %v, want %v", i, got, test.due)
+		}
+	}
+}
+
+func TestRollbackAlias(t *testing.T) {
+	useTestRoot(t)
+	for _, version := range []string{"2.70.0", "2.71.0", "2.72.0"} {
+		if err := WriteAlias("stable", version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []string{"2.71.0", "2.70.0"} {
+		if got, err := RollbackAlias("stable"); err != nil || got != want {
+			t.Fatalf("got %s, %v, want %s", got, err, want)
+		}
+	}
+	if _, err := RollbackAlias("stable"); err == nil {
+		t.Error("rolled back past the first target")
+	}
+	if version, _ := ResolvePersonalAlias("stable"); version != "2.70.0" {
+		t.Errorf("stable points at %s, want 2.70.0", version)
+	}
+}
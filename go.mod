@@ -7,6 +7,8 @@ require (
 	github.com/sergi/go-diff v1.3.1
 	github.com/urfave/cli/v2 v2.27.6
 	golang.org/x/sync v0.6.0
+	golang.org/x/term v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
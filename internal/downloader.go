@@ -33,6 +33,12 @@ func mapPlatform(goos, arch string) (string, error) {
 	return "", fmt.Errorf("unsupported platform: %s-%s", goos, arch)
 }
 
+// PlatformName returns the release platform of the running host, as used in download URLs
+// and project manifest checksums (e.g. linux-amd64, mac-arm64).
+func PlatformName() (string, error) {
+	return mapPlatform(runtime.GOOS, runtime.GOARCH)
+}
+
 func DownloadAndInstall(version string) error {
 	platform, err := mapPlatform(runtime.GOOS, runtime.GOARCH)
 	if err != nil {
@@ -46,7 +52,7 @@ func DownloadAndInstall(version string) error {
 	os.MkdirAll(dir, 0755)
 	binPath := filepath.Join(dir, utils.BinaryName)
 
-	out, err := os.Create(binPath)
+	out, err := utils.CreateFileReplacing(binPath)
 	if err != nil {
 		return fmt.Errorf("failed to create binary file: %w", err)
 	}
@@ -66,6 +72,9 @@ func DownloadAndInstall(version string) error {
 	if err != nil {
 		return fmt.Errorf("failed to write binary: %w", err)
 	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
 
 	if err := os.Chmod(binPath, 0755); err != nil {
 		return fmt.Errorf("chmod failed: %w", err)
@@ -75,5 +84,10 @@ func DownloadAndInstall(version string) error {
 		_ = exec.Command("xattr", "-c", binPath).Run()
 	}
 
+	// Reinstalled versions are often byte-identical to ones already on disk
+	if linked, err := utils.DedupBinaries(version, false); err == nil && len(linked) > 0 {
+		fmt.Printf("🔗 %s is identical to %s, stored once\n", version, linked[0].LinkedTo)
+	}
+
 	return nil
 }
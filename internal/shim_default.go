@@ -0,0 +1,7 @@
+//go:build !shim_embedded
+
+package internal
+
+// EmbeddedShim is empty when jfvm is built without the shim_embedded tag;
+// setup then falls back to a shim built alongside the jfvm executable.
+var EmbeddedShim []byte
@@ -0,0 +1,10 @@
+//go:build shim_embedded
+
+package internal
+
+import _ "embed"
+
+// EmbeddedShim is the jf shim binary compiled into jfvm by `make build`.
+//
+//go:embed embedded/jf
+var EmbeddedShim []byte
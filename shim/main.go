@@ -7,10 +7,18 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// shimDepthEnv counts nested shim invocations. jf legitimately runs jf (e.g. from plugins),
+// but a version that resolves back to the shim would otherwise exec itself forever.
+const (
+	shimDepthEnv = "JFVM_SHIM_DEPTH"
+	maxShimDepth = 5
+)
+
 type HistoryEntry struct {
 	Version   string    `json:"version"`
 	Timestamp time.Time `json:"timestamp"`
@@ -39,6 +47,17 @@ func main() {
 		fmt.Printf("[shim] Full binary path: %s\n", bin)
 	}
 
+	depth, _ := strconv.Atoi(os.Getenv(shimDepthEnv))
+	if depth >= maxShimDepth {
+		fmt.Fprintf(os.Stderr, "[shim] Recursive invocation detected: jf was started through the jfvm shim %d times in a row.\n", depth)
+		fmt.Fprintf(os.Stderr, "[shim] Version %s probably points back at the shim. Run `jfvm doctor` to find out.\n", version)
+		os.Exit(1)
+	}
+	if isShim(bin) {
+		fmt.Fprintf(os.Stderr, "[shim] Version %s is the jfvm shim itself, not a JFrog CLI binary. Re-link or reinstall it.\n", version)
+		os.Exit(1)
+	}
+
 	// Record start time for history
 	startTime := time.Now()
 	command := strings.Join(os.Args[1:], " ")
@@ -48,6 +67,7 @@ func main() {
 
 	cmd := exec.Command(bin, os.Args[1:]...)
 	cmd.Stdin = os.Stdin
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", shimDepthEnv, depth+1))
 
 	// Capture output while also writing to the original streams
 	cmd.Stdout = &stdout
@@ -82,6 +102,23 @@ func main() {
 	}
 }
 
+// isShim reports whether bin resolves to the running shim executable.
+func isShim(bin string) bool {
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	selfInfo, err := os.Stat(self)
+	if err != nil {
+		return false
+	}
+	binInfo, err := os.Stat(bin)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(selfInfo, binInfo)
+}
+
 func addHistoryEntry(home, version, command string, duration time.Duration, exitCode int, stdout, stderr string) {
 	historyFile := filepath.Join(home, ".jfvm", "history.json")
 
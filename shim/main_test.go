@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// runAsShimEnv makes the test binary run the shim's main instead of the tests.
+const runAsShimEnv = "JFVM_TEST_RUN_SHIM"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runAsShimEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// installShimCopy copies the test binary to path, so it can act as the shim or as a version.
+func installShimCopy(t *testing.T, path string) {
+	t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runShim runs shim with version active, as if started depth shims deep, and returns its
+// exit code and stderr.
+func runShim(t *testing.T, home, shim, version, depth string) (int, string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(home, ".jfvm", "config"), []byte(version), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(shim, "--version")
+	cmd.Env = append(os.Environ(), "HOME="+home, runAsShimEnv+"=1", shimDepthEnv+"="+depth)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), stderr.String()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 0, stderr.String()
+}
+
+func TestShimRefusesToRunItself(t *testing.T) {
+	home := t.TempDir()
+	shim := filepath.Join(home, ".jfvm", "shim", "jf")
+	installShimCopy(t, shim)
+	version := filepath.Join(home, ".jfvm", "versions", "self", "jf")
+	if err := os.MkdirAll(filepath.Dir(version), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(shim, version); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	code, stderr := runShim(t, home, shim, "self", "")
+	if code != 1 || !strings.Contains(stderr, "Version self is the jfvm shim itself") {
+		t.Errorf("exit %d, stderr:\n%s", code, stderr)
+	}
+}
+
+func TestShimStopsNestedInvocations(t *testing.T) {
+	home := t.TempDir()
+	shim := filepath.Join(home, ".jfvm", "shim", "jf")
+	installShimCopy(t, shim)
+	// A copy of the shim isn't the same file, so only the depth guard stops a chain of them
+	installShimCopy(t, filepath.Join(home, ".jfvm", "versions", "loop", "jf"))
+
+	code, stderr := runShim(t, home, shim, "loop", strconv.Itoa(maxShimDepth))
+	if code != 1 || !strings.Contains(stderr, "Recursive invocation detected") {
+		t.Errorf("exit %d, stderr:\n%s", code, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".jfvm", "history.json")); err == nil {
+		t.Error("a refused invocation was recorded in the history")
+	}
+}